@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func main() {
+	var action = flag.String("action", "create", "Backup action: create, restore, inspect")
+	var file = flag.String("file", "", "Archive path (default backups/<environment>-<timestamp>.tar.gz for create)")
+	flag.Parse()
+
+	// inspect 只读取清单，不需要连接数据库
+	if *action == "inspect" {
+		manifest := readManifest(*file)
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(manifest); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 加载配置
+	cfg := config.Load()
+
+	// 初始化日志器
+	if err := logger.InitWithOptions(cfg.LoggerOptions()); err != nil {
+		panic(err)
+	}
+
+	// 连接数据库
+	db, err := database.Init(cfg.Database)
+	if err != nil {
+		logger.Fatal("数据库连接失败", zap.Error(err))
+	}
+
+	switch *action {
+	case "create":
+		path := *file
+		if path == "" {
+			path = filepath.Join("backups", fmt.Sprintf("%s-%s.tar.gz", cfg.Environment, time.Now().Format("20060102150405")))
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			logger.Fatal("创建备份目录失败", zap.Error(err))
+		}
+
+		// 先写入临时文件，成功后再重命名，避免留下不完整的归档
+		tmp := path + ".partial"
+		f, err := os.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err != nil {
+			logger.Fatal("创建备份文件失败", zap.Error(err))
+		}
+		manifest, err := database.WriteBackup(db, f, cfg.Environment)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(tmp)
+			logger.Fatal("数据库备份失败", zap.Error(err))
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			logger.Fatal("保存备份文件失败", zap.Error(err))
+		}
+		logger.Info("Backup created successfully ✅",
+			zap.String("file", path),
+			zap.String("schema_version", manifest.SchemaVersion),
+			zap.Int("tables", len(manifest.Tables)))
+
+	case "restore":
+		if *file == "" {
+			logger.Fatal("restore 需要通过 -file 指定归档")
+		}
+		manifest := readManifest(*file)
+		if err := database.CheckBackupCompatible(manifest); err != nil {
+			logger.Fatal("归档与当前版本不兼容", zap.Error(err))
+		}
+
+		// 先把数据库结构迁移到最新版本，再按当前模型写入数据
+		if err := database.RunMigrations(db, cfg); err != nil {
+			logger.Fatal("数据库迁移失败", zap.Error(err))
+		}
+
+		f, err := os.Open(*file)
+		if err != nil {
+			logger.Fatal("打开备份文件失败", zap.Error(err))
+		}
+		defer f.Close()
+		if _, err := database.RestoreBackup(db, f); err != nil {
+			logger.Fatal("数据库恢复失败", zap.Error(err))
+		}
+		logger.Info("Backup restored successfully ✅",
+			zap.String("file", *file),
+			zap.String("from_environment", manifest.Environment),
+			zap.Time("created_at", manifest.CreatedAt))
+
+	default:
+		logger.Fatal("未知操作", zap.String("action", *action))
+	}
+}
+
+// readManifest 读取归档清单，失败时退出
+func readManifest(path string) *database.BackupManifest {
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "-file is required")
+		os.Exit(1)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	manifest, err := database.ReadBackupManifest(f)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return manifest
+}
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
@@ -11,15 +18,44 @@ import (
 )
 
 func main() {
-	var action = flag.String("action", "up", "Migration action: up, down, status, reset")
-	var migrationID = flag.String("id", "", "Migration ID for rollback")
+	var action = flag.String("action", "up", "Migration action: up, down, redo, status, verify, create, dry-run, reset")
+	var migrationID = flag.String("id", "", "Migration ID for rollback (down)")
+	var target = flag.String("to", "", "Apply migrations up to and including this ID (up, versioned environments only)")
+	var steps = flag.Int("steps", 1, "Number of migrations to roll back (down)")
+	var dryRun = flag.Bool("dry-run", false, "Print SQL without executing (up in versioned environments only, down, redo)")
+	var jsonOutput = flag.Bool("json", false, "Print status as JSON (status)")
+	var name = flag.String("name", "", "Migration name (create), e.g. add_roles_table")
+	var dir = flag.String("dir", "migrations", "Migration files directory (create)")
+	var yes = flag.Bool("yes", false, "Skip the interactive confirmation (reset)")
+	var confirm = flag.String("confirm", "", "Confirmation token required to reset a production database: reset-<db name> (reset)")
+	var backup = flag.String("backup", "", "Back up the database with pg_dump to this file before dropping tables (reset)")
 	flag.Parse()
 
+	// create 只生成文件，不需要连接数据库；名称也可以作为位置参数传入
+	if *action == "create" {
+		if *name == "" {
+			*name = flag.Arg(0)
+		}
+		upPath, downPath, err := database.CreateMigrationFiles(*dir, *name, time.Now())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Created %s\nCreated %s\n", upPath, downPath)
+		return
+	}
+	if *action == "dry-run" {
+		*action = "up"
+		*dryRun = true
+	}
+
 	// 加载配置
 	cfg := config.Load()
 
 	// 初始化日志器
-	logger.Init(cfg.LogLevel)
+	if err := logger.InitWithOptions(cfg.LoggerOptions()); err != nil {
+		panic(err)
+	}
 
 	// 连接数据库
 	db, err := database.Init(cfg.Database)
@@ -29,19 +65,74 @@ func main() {
 
 	switch *action {
 	case "up":
-		if err := database.RunMigrations(db, cfg); err != nil {
+		// 开发和测试环境的 up 使用 AutoMigrate，不会执行版本化迁移，
+		// 因此不支持只对版本化迁移有意义的 -to 和 dry-run，避免预览的 SQL 与实际执行不一致
+		if database.UsesAutoMigrate(cfg.Environment) && (*dryRun || *target != "") {
+			logger.Fatal("当前环境使用 AutoMigrate，不执行版本化迁移；-to 和 dry-run 只适用于 production 环境",
+				zap.String("environment", cfg.Environment))
+		}
+		if *dryRun {
+			pending, err := database.PlanUp(db, *target)
+			if err != nil {
+				logger.Fatal("生成迁移计划失败", zap.Error(err))
+			}
+			printPlan("up", pending)
+			return
+		}
+
+		if *target != "" {
+			err = database.MigrateUp(db, *target)
+		} else {
+			err = database.RunMigrations(db, cfg)
+		}
+		if err != nil {
 			logger.Fatal("数据库迁移失败", zap.Error(err))
 		}
 		logger.Info("Migrations completed successfully ✅")
 
 	case "down":
-		if *migrationID == "" {
-			logger.Fatal("回滚操作需要迁移ID")
+		if *migrationID != "" {
+			if *dryRun {
+				logger.Fatal("-dry-run 仅支持按 -steps 回滚")
+			}
+			if err := database.RollbackMigration(db, *migrationID); err != nil {
+				logger.Fatal("迁移回滚失败", zap.String("migration_id", *migrationID), zap.Error(err))
+			}
+			logger.Info("Migration rolled back successfully ✅", zap.String("migration_id", *migrationID))
+			return
+		}
+
+		if *dryRun {
+			targets, err := database.PlanDown(db, *steps)
+			if err != nil {
+				logger.Fatal("生成回滚计划失败", zap.Error(err))
+			}
+			printPlan("down", targets)
+			return
+		}
+
+		rolledBack, err := database.MigrateDown(db, *steps)
+		if err != nil {
+			logger.Fatal("迁移回滚失败", zap.Strings("rolled_back", rolledBack), zap.Error(err))
 		}
-		if err := database.RollbackMigration(db, *migrationID); err != nil {
-			logger.Fatal("迁移回滚失败", zap.String("migration_id", *migrationID), zap.Error(err))
+		logger.Info("Migrations rolled back successfully ✅", zap.Strings("migration_ids", rolledBack))
+
+	case "redo":
+		if *dryRun {
+			targets, err := database.PlanDown(db, 1)
+			if err != nil {
+				logger.Fatal("生成迁移计划失败", zap.Error(err))
+			}
+			printPlan("down", targets)
+			printPlan("up", targets)
+			return
 		}
-		logger.Info("Migration rolled back successfully ✅", zap.String("migration_id", *migrationID))
+
+		id, err := database.RedoMigration(db)
+		if err != nil {
+			logger.Fatal("重新执行迁移失败", zap.Error(err))
+		}
+		logger.Info("Migration redone successfully ✅", zap.String("migration_id", id))
 
 	case "status":
 		status, err := database.GetMigrationStatus(db)
@@ -49,28 +140,70 @@ func main() {
 			logger.Fatal("获取迁移状态失败", zap.Error(err))
 		}
 
-		logger.Info("Migration Status:")
-		for _, s := range status {
-			if s.Executed {
-				logger.Info("Executed ✅", zap.String("id", s.ID), zap.Time("executed_at", s.ExecutedAt))
-			} else {
-				logger.Info("Not executed ❌", zap.String("id", s.ID))
+		if *jsonOutput {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(status); err != nil {
+				logger.Fatal("输出迁移状态失败", zap.Error(err))
 			}
+			return
+		}
+		printStatus(status)
+
+	case "verify":
+		// 检查已执行迁移是否被修改，以及数据库结构是否与模型一致
+		failed := false
+		if err := database.VerifyChecksums(db); err != nil {
+			logger.Error("迁移校验和检查失败", zap.Error(err))
+			failed = true
+		}
+
+		issues, err := database.VerifySchema(db)
+		if err != nil {
+			logger.Fatal("数据库结构检查失败", zap.Error(err))
+		}
+		for _, issue := range issues {
+			logger.Warn("Schema drift ❌", zap.String("table", issue.Table), zap.String("kind", issue.Kind),
+				zap.String("name", issue.Name), zap.String("detail", issue.Detail))
+		}
+
+		if failed || len(issues) > 0 {
+			logger.Error("Verification failed ❌", zap.Int("schema_issues", len(issues)))
+			os.Exit(1)
 		}
+		logger.Info("Migrations and schema verified successfully ✅")
 
 	case "reset":
-		// 警告：这会删除所有数据
-		logger.Warn("⚠️  WARNING: This will drop all tables and recreate them!")
-		logger.Info("Are you sure? (y/N): ")
+		// 警告：这会删除当前模式下的所有表；生产环境必须通过 -confirm 提供确认口令
+		if err := database.CheckResetAllowed(cfg.Environment, cfg.Database, *confirm); err != nil {
+			logger.Fatal("拒绝重置数据库", zap.String("environment", cfg.Environment), zap.Error(err))
+		}
 
-		var confirm string
-		fmt.Scanln(&confirm)
+		tables, err := database.ListTables(db)
+		if err != nil {
+			logger.Fatal("获取数据表失败", zap.Error(err))
+		}
+		logger.Warn("⚠️  WARNING: This will drop all tables and recreate them!",
+			zap.String("environment", cfg.Environment),
+			zap.String("database", cfg.Database.Name),
+			zap.String("schema", cfg.Database.Schema),
+			zap.Strings("tables", tables))
 
-		if confirm != "y" && confirm != "Y" {
+		if !*yes && !confirmReset() {
 			logger.Info("Operation cancelled")
 			os.Exit(0)
 		}
 
+		// 删除前备份
+		if *backup != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+			err := database.BackupDatabase(ctx, cfg.Database, *backup)
+			cancel()
+			if err != nil {
+				logger.Fatal("数据库备份失败，已取消重置", zap.Error(err))
+			}
+		}
+
 		// 删除所有表
 		if err := database.ResetDatabase(db); err != nil {
 			logger.Fatal("数据库重置失败", zap.Error(err))
@@ -87,3 +220,61 @@ func main() {
 		logger.Fatal("未知操作", zap.String("action", *action))
 	}
 }
+
+// printPlan 输出将要执行的迁移 SQL（dry-run）
+func printPlan(direction string, plan []database.Migration) {
+	if len(plan) == 0 {
+		fmt.Printf("-- no migrations to run (%s)\n", direction)
+		return
+	}
+	for _, m := range plan {
+		sql := m.UpSQL
+		if direction == "down" {
+			sql = m.DownSQL
+		}
+		fmt.Printf("-- %s %s\n", direction, m.ID)
+		if sql == "" {
+			fmt.Println("-- Go migration, SQL is not available in dry-run")
+		} else {
+			fmt.Println(sql)
+		}
+		fmt.Println()
+	}
+}
+
+// printStatus 以表格形式输出迁移状态
+func printStatus(status []database.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tID\tSTATUS\tEXECUTED AT\tNOTE")
+
+	pending := 0
+	for _, s := range status {
+		state := "pending"
+		executedAt := "-"
+		switch {
+		case s.Modified:
+			state = "modified"
+		case s.Executed:
+			state = "applied"
+		default:
+			pending++
+		}
+		if s.ExecutedAt != nil {
+			executedAt = s.ExecutedAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", s.Version, s.ID, state, executedAt, s.Note)
+	}
+	w.Flush()
+	fmt.Printf("\n%d migrations, %d pending\n", len(status), pending)
+}
+
+// confirmReset 交互式确认重置，标准输入不可读（如 CI 中）时视为取消
+func confirmReset() bool {
+	fmt.Fprint(os.Stderr, "Are you sure? (y/N): ")
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	answer = strings.TrimSpace(answer)
+	return answer == "y" || answer == "Y"
+}
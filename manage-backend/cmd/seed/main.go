@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func main() {
+	var dir = flag.String("dir", "", "Seed files root directory, files are read from <dir>/<environment> (default config/seeds)")
+	var only = flag.String("only", "", "Comma-separated fixture kinds to seed: roles, settings, users (default all)")
+	var truncate = flag.Bool("truncate", false, "Delete existing rows of the selected kinds before seeding (not allowed in production)")
+	var fakeUsers = flag.Int("fake-users", 0, "Number of fake users to generate for load testing (not allowed in production)")
+	var fakePassword = flag.String("fake-password", "", "Password for generated fake users (default password123)")
+	flag.Parse()
+
+	// 加载配置
+	cfg := config.Load()
+
+	// 初始化日志器
+	if err := logger.InitWithOptions(cfg.LoggerOptions()); err != nil {
+		panic(err)
+	}
+
+	// 连接数据库
+	db, err := database.Init(cfg.Database)
+	if err != nil {
+		logger.Fatal("数据库连接失败", zap.Error(err))
+	}
+
+	opts := database.SeedOptions{
+		Dir:          *dir,
+		Truncate:     *truncate,
+		FakeUsers:    *fakeUsers,
+		FakePassword: *fakePassword,
+	}
+	if *only != "" {
+		opts.Only = strings.Split(*only, ",")
+	}
+
+	if err := database.Seed(db, cfg.Environment, opts); err != nil {
+		logger.Fatal("种子数据写入失败", zap.Error(err))
+	}
+	logger.Info("Database seeded successfully ✅", zap.String("environment", cfg.Environment))
+}
@@ -1,17 +1,31 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/handler"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/middleware"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/health"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/lifecycle"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/tracing"
 	_ "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/docs" // 导入生成的 docs
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // @title Go 管理系统启动器 API
@@ -37,23 +51,60 @@ func main() {
 	cfg := config.Load()
 
 	// 初始化日志器
-	logger.Init(cfg.LogLevel)
+	if err := logger.InitWithOptions(cfg.LoggerOptions()); err != nil {
+		panic(err)
+	}
 
 	// 记录配置详情
 	config.LogConfigDetails(cfg)
 
+	// 收到 SIGHUP 时重新读取配置中的日志级别
+	go reloadLogLevelOnSIGHUP()
+
+	// 关闭钩子按注册的逆序执行：先停止使用依赖的组件，最后关闭数据库和链路追踪
+	lc := lifecycle.NewManager()
+
+	// 初始化链路追踪
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, cfg.Environment)
+	if err != nil {
+		logger.Fatal("链路追踪初始化失败", zap.Error(err))
+	}
+	lc.OnShutdown("tracing", lifecycle.HookFunc(shutdownTracing))
+
 	// 初始化数据库
 	db, err := database.Init(cfg.Database)
 	if err != nil {
 		logger.Fatal("数据库初始化失败", zap.Error(err))
 	}
+	lc.OnShutdown("database", func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	})
+
+	if cfg.Tracing.Enabled {
+		if err := db.Use(tracing.NewGormPlugin(tracing.DBSystem(db.Dialector.Name()))); err != nil {
+			logger.Fatal("数据库链路追踪初始化失败", zap.Error(err))
+		}
+	}
 
 	// 运行数据库迁移
 	if err := database.RunMigrations(db, cfg); err != nil {
 		logger.Fatal("数据库迁移失败", zap.Error(err))
 	}
 
-	// 如需种子数据，可以手动调用: database.SeedDatabase(db, cfg.Environment)
+	// 只读副本在迁移之后启用，关闭时先于主库关闭
+	replicas, err := database.UseReplicas(db, cfg.Database)
+	if err != nil {
+		logger.Fatal("数据库只读副本初始化失败", zap.Error(err))
+	}
+	if replicas != nil {
+		lc.OnShutdown("database replicas", replicas.Close)
+	}
+
+	// 如需种子数据，运行: make seed（即 go run ./cmd/seed，种子文件位于 config/seeds/<环境>）
 	logger.Info("✅ 数据库连接成功")
 
 	// 根据环境初始化 Gin 路由器设置
@@ -69,26 +120,119 @@ func main() {
 		logger.Info("🔧 运行在开发模式", zap.String("environment", cfg.Environment))
 	}
 
+	// 中间件顺序：请求ID和追踪最先执行，访问日志和指标包在 Recovery 外层以记录 panic 产生的 500
 	router := gin.New()
-	router.Use(gin.Logger())
+	router.Use(middleware.RequestID())
+	if cfg.Tracing.Enabled {
+		router.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	}
+	router.Use(middleware.AccessLog())
+	if cfg.Metrics.Enabled {
+		router.Use(middleware.Metrics())
+	}
 	router.Use(gin.Recovery())
 	router.Use(middleware.CORS())
+	if replicas != nil {
+		router.Use(middleware.StickyPrimary())
+	}
+
+	// 就绪检查：数据库连接和迁移状态，Redis 在 SetupRoutes 中注册
+	checker := health.NewChecker(0)
+	checker.Register("database", func(ctx context.Context) (string, error) {
+		return database.Ping(ctx, db)
+	})
+	checker.Register("migrations", func(ctx context.Context) (string, error) {
+		return database.CheckMigrations(ctx, db)
+	})
+	if replicas != nil {
+		// 副本不可用时读操作回退到主库，只标记为 degraded
+		checker.RegisterOptional("database_replicas", replicas.Check)
+	}
+	lc.OnDrain(checker.SetDraining)
 
 	// API 路由
 	api := router.Group("/api/v1")
-	handler.SetupRoutes(api, db)
+	handler.SetupRoutes(api, db, checker, lc)
 
 	// Swagger 文档
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// 健康检查
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
+	// 存活和就绪检查，/health 保留为 /livez 的别名
+	healthHandler := handler.NewHealthHandler(checker)
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/health", healthHandler.Livez)
+
+	// 监控指标
+	if cfg.Metrics.Enabled {
+		setupMetrics(router, db, cfg.Metrics, cfg.Database.Name, lc)
+	}
+
+	// 启动服务器，收到 SIGINT/SIGTERM 后排空请求并按顺序关闭
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           router,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	err = lc.Run(srv, lifecycle.Options{
+		DrainPeriod:     cfg.Server.DrainPeriod,
+		ShutdownTimeout: cfg.Server.ShutdownTimeout,
 	})
+	if err != nil {
+		logger.Error("服务关闭时出现错误", zap.Error(err))
+		_ = logger.Logger.Sync()
+		os.Exit(1)
+	}
+	logger.Info("服务已退出")
+	_ = logger.Logger.Sync()
+}
+
+// reloadLogLevelOnSIGHUP 收到 SIGHUP 信号时重新加载配置，并将全局日志级别恢复为配置中的 log_level
+// 通过管理接口设置的命名日志器级别不受影响
+func reloadLogLevelOnSIGHUP() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	for range sigCh {
+		cfg := config.Load()
+		if err := logger.SetLevel("", cfg.LogLevel, 0); err != nil {
+			logger.Error("重新加载日志级别失败", zap.String("level", cfg.LogLevel), zap.Error(err))
+			continue
+		}
+		logger.Info("已重新加载日志级别", zap.String("level", cfg.LogLevel))
+	}
+}
+
+// setupMetrics 注册数据库指标并暴露指标端点
+// 配置了独立监听地址时在单独的端口上提供服务，否则挂载在业务路由上
+func setupMetrics(router *gin.Engine, db *gorm.DB, cfg config.MetricsConfig, dbName string, lc *lifecycle.Manager) {
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.RegisterDBStats(sqlDB, dbName)
+	} else {
+		logger.Warn("获取数据库连接池失败，跳过数据库指标", zap.Error(err))
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = config.GetDefaultMetricsConfig().Path
+	}
 
-	// 启动服务器
-	logger.Info("服务器正在启动", zap.String("port", cfg.Port))
-	if err := router.Run(":" + cfg.Port); err != nil {
-		logger.Fatal("服务器启动失败", zap.Error(err))
+	if cfg.ListenAddr == "" {
+		router.GET(path, gin.WrapH(metrics.Handler()))
+		logger.Info("监控指标已挂载", zap.String("path", path))
+		return
 	}
-}
\ No newline at end of file
+
+	mux := http.NewServeMux()
+	mux.Handle(path, metrics.Handler())
+	srv := &http.Server{Addr: cfg.ListenAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		logger.Info("监控指标服务正在启动", zap.String("addr", cfg.ListenAddr), zap.String("path", path))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("监控指标服务异常退出", zap.Error(err))
+		}
+	}()
+	lc.OnShutdown("metrics server", srv.Shutdown)
+}
@@ -0,0 +1,59 @@
+package config
+
+import "time"
+
+// CacheConfig 缓存配置
+type CacheConfig struct {
+	// 缓存驱动: redis(默认), memory(进程内存储，仅适用于单节点部署和测试)
+	Driver string `mapstructure:"driver" yaml:"driver"`
+
+	// 内存存储清理过期键的间隔
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval" yaml:"cleanup_interval"`
+
+	// 用户信息读缓存
+	User UserCacheConfig `mapstructure:"user" yaml:"user"`
+}
+
+// UserCacheConfig 用户信息读缓存配置
+type UserCacheConfig struct {
+	// 是否启用用户缓存
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// 用户信息缓存时间
+	TTL time.Duration `mapstructure:"ttl" yaml:"ttl"`
+
+	// 不存在的用户ID缓存时间（防止缓存穿透）
+	NegativeTTL time.Duration `mapstructure:"negative_ttl" yaml:"negative_ttl"`
+}
+
+// GetDefaultCacheConfig 获取默认缓存配置
+func GetDefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Driver:          "redis",
+		CleanupInterval: time.Minute,
+		User: UserCacheConfig{
+			Enabled:     true,
+			TTL:         10 * time.Minute,
+			NegativeTTL: 30 * time.Second,
+		},
+	}
+}
+
+// withDefaults 为未设置的字段填充默认值
+// User.Enabled 是布尔值，无法区分未设置和 false，其默认值在 Load 中通过 viper.SetDefault 设置
+func (c CacheConfig) withDefaults() CacheConfig {
+	defaults := GetDefaultCacheConfig()
+	if c.Driver == "" {
+		c.Driver = defaults.Driver
+	}
+	if c.CleanupInterval <= 0 {
+		c.CleanupInterval = defaults.CleanupInterval
+	}
+	if c.User.TTL <= 0 {
+		c.User.TTL = defaults.User.TTL
+	}
+	if c.User.NegativeTTL <= 0 {
+		c.User.NegativeTTL = defaults.User.NegativeTTL
+	}
+	return c
+}
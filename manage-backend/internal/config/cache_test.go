@@ -0,0 +1,24 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheConfigWithDefaults(t *testing.T) {
+	// 只设置驱动时其余字段使用默认值
+	cfg := CacheConfig{Driver: "memory", User: UserCacheConfig{Enabled: true}}.withDefaults()
+	assert.Equal(t, "memory", cfg.Driver)
+	assert.Equal(t, time.Minute, cfg.CleanupInterval)
+	assert.True(t, cfg.User.Enabled)
+	assert.Equal(t, 10*time.Minute, cfg.User.TTL)
+	assert.Equal(t, 30*time.Second, cfg.User.NegativeTTL)
+
+	// 显式关闭用户缓存时不会被默认值覆盖
+	cfg = CacheConfig{User: UserCacheConfig{Enabled: false, TTL: time.Minute}}.withDefaults()
+	assert.Equal(t, "redis", cfg.Driver)
+	assert.False(t, cfg.User.Enabled)
+	assert.Equal(t, time.Minute, cfg.User.TTL)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -10,27 +11,97 @@ import (
 type Config struct {
 	Environment string        `mapstructure:"environment"`
 	Port        string        `mapstructure:"port"`
+	Server      ServerConfig  `mapstructure:"server"`
 	LogLevel    string        `mapstructure:"log_level"`
+	Log         LogConfig     `mapstructure:"log"`
 	Database    Database      `mapstructure:"database"`
 	Redis       Redis         `mapstructure:"redis"`
 	JWT         JWT           `mapstructure:"jwt"`
 	Captcha     CaptchaConfig `mapstructure:"captcha"`
+	Session     SessionConfig `mapstructure:"session"`
+	Cache       CacheConfig   `mapstructure:"cache"`
+	Metrics     MetricsConfig `mapstructure:"metrics"`
+	Tracing     TracingConfig `mapstructure:"tracing"`
+	Export      ExportConfig  `mapstructure:"export"`
 }
 
 type Database struct {
+	// 数据库驱动: postgres(默认), mysql, sqlite
+	Driver string `mapstructure:"driver"`
+
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
-	Name     string `mapstructure:"name"`
-	Schema   string `mapstructure:"schema"`
+	Name     string `mapstructure:"name"`   // sqlite 为数据库文件路径，:memory: 表示内存数据库
+	Schema   string `mapstructure:"schema"` // 仅 postgres 使用
+
+	// TLS: ssl_mode 取值 disable, allow, prefer, require, verify-ca, verify-full（与 PostgreSQL 一致）
+	SSLMode     string `mapstructure:"ssl_mode"`
+	SSLRootCert string `mapstructure:"ssl_root_cert"` // CA 证书文件
+	SSLCert     string `mapstructure:"ssl_cert"`      // 客户端证书文件
+	SSLKey      string `mapstructure:"ssl_key"`       // 客户端私钥文件
+
+	// 会话时区，如 Asia/Shanghai
+	TimeZone string `mapstructure:"timezone"`
+	// 单条语句的超时时间，0 表示不限制（mysql 只对 SELECT 生效）
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"`
+	// 连接标识，便于在数据库端区分连接来源
+	ApplicationName string `mapstructure:"application_name"`
+
+	// 连接池
+	Pool DatabasePoolConfig `mapstructure:"pool"`
+	// 启动时连接失败的重试策略
+	ConnectRetry DatabaseRetryConfig `mapstructure:"connect_retry"`
+
+	// 只读副本，列表和查询类读操作路由到健康的副本，写操作和写后的读使用主库
+	Replicas []DatabaseReplica `mapstructure:"replicas"`
+	// 副本健康检查间隔
+	ReplicaCheckInterval time.Duration `mapstructure:"replica_check_interval"`
+
+	// SQL 日志
+	Log DatabaseLogConfig `mapstructure:"log"`
 }
 
 type Redis struct {
+	// 部署模式: standalone(默认), sentinel, cluster
+	Mode     string `mapstructure:"mode"`
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
+	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
-	DB       int    `mapstructure:"db"`
+	DB       int    `mapstructure:"db"` // cluster 模式不支持
+
+	// sentinel 模式为哨兵地址，cluster 模式为集群节点地址
+	Addrs            []string `mapstructure:"addrs"`
+	MasterName       string   `mapstructure:"master_name"`
+	SentinelUsername string   `mapstructure:"sentinel_username"`
+	SentinelPassword string   `mapstructure:"sentinel_password"`
+
+	TLS RedisTLS `mapstructure:"tls"`
+
+	// 连接池与超时设置，0 表示使用 go-redis 默认值
+	PoolSize        int           `mapstructure:"pool_size"`
+	MinIdleConns    int           `mapstructure:"min_idle_conns"`
+	MaxRetries      int           `mapstructure:"max_retries"`
+	DialTimeout     time.Duration `mapstructure:"dial_timeout"`
+	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
+	PoolTimeout     time.Duration `mapstructure:"pool_timeout"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+
+	// 全局键前缀，多个环境或应用共用一个 Redis 时避免键冲突
+	KeyPrefix string `mapstructure:"key_prefix"`
+}
+
+// RedisTLS Redis TLS 配置
+type RedisTLS struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"ca_file"`   // 自定义 CA 证书
+	CertFile           string `mapstructure:"cert_file"` // 客户端证书（双向 TLS）
+	KeyFile            string `mapstructure:"key_file"`  // 客户端私钥（双向 TLS）
+	ServerName         string `mapstructure:"server_name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
 }
 
 type JWT struct {
@@ -82,20 +153,43 @@ func Load() *Config {
 	// 将环境变量映射到配置键
 	viper.BindEnv("port", "PORT")
 	viper.BindEnv("log_level", "LOG_LEVEL")
+	viper.BindEnv("log.output", "LOG_OUTPUT")
+	viper.BindEnv("log.file.path", "LOG_FILE_PATH")
+	viper.BindEnv("database.driver", "DB_DRIVER")
 	viper.BindEnv("database.host", "DB_HOST")
 	viper.BindEnv("database.port", "DB_PORT")
 	viper.BindEnv("database.user", "DB_USER")
 	viper.BindEnv("database.password", "DB_PASSWORD")
 	viper.BindEnv("database.name", "DB_NAME")
 	viper.BindEnv("database.schema", "DB_SCHEMA")
+	viper.BindEnv("database.ssl_mode", "DB_SSL_MODE")
+	viper.BindEnv("database.ssl_root_cert", "DB_SSL_ROOT_CERT")
+	viper.BindEnv("database.ssl_cert", "DB_SSL_CERT")
+	viper.BindEnv("database.ssl_key", "DB_SSL_KEY")
+	viper.BindEnv("database.log.level", "DB_LOG_LEVEL")
 	viper.BindEnv("redis.host", "REDIS_HOST")
 	viper.BindEnv("redis.port", "REDIS_PORT")
 	viper.BindEnv("redis.password", "REDIS_PASSWORD")
 	viper.BindEnv("redis.db", "REDIS_DB")
+	viper.BindEnv("redis.mode", "REDIS_MODE")
+	viper.BindEnv("redis.username", "REDIS_USERNAME")
+	viper.BindEnv("redis.master_name", "REDIS_MASTER_NAME")
+	viper.BindEnv("redis.sentinel_password", "REDIS_SENTINEL_PASSWORD")
+	viper.BindEnv("redis.key_prefix", "REDIS_KEY_PREFIX")
+	viper.BindEnv("cache.driver", "CACHE_DRIVER")
 	viper.BindEnv("jwt.secret", "JWT_SECRET")
 	viper.BindEnv("jwt.expire_time", "JWT_EXPIRE_TIME")
 	viper.BindEnv("jwt.access_token_expire", "JWT_ACCESS_TOKEN_EXPIRE")
 	viper.BindEnv("jwt.refresh_token_expire", "JWT_REFRESH_TOKEN_EXPIRE")
+	viper.BindEnv("session.failure_mode", "SESSION_FAILURE_MODE")
+	viper.BindEnv("metrics.enabled", "METRICS_ENABLED")
+	viper.BindEnv("metrics.listen_addr", "METRICS_LISTEN_ADDR")
+	viper.BindEnv("tracing.enabled", "TRACING_ENABLED")
+	viper.BindEnv("tracing.exporter", "TRACING_EXPORTER")
+	viper.BindEnv("tracing.endpoint", "TRACING_ENDPOINT")
+
+	// 布尔配置的默认值，只在配置文件和环境变量都未设置时生效
+	viper.SetDefault("cache.user.enabled", GetDefaultCacheConfig().User.Enabled)
 
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
@@ -105,6 +199,11 @@ func Load() *Config {
 
 	// 在配置中设置环境类型
 	config.Environment = environment
+	config.Server = config.Server.withDefaults()
+	config.Database = config.Database.WithDefaults()
+	config.Cache = config.Cache.withDefaults()
+	config.Session = config.Session.WithDefaults()
+	config.Export = config.Export.withDefaults()
 
 	return &config
 }
\ No newline at end of file
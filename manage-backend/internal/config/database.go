@@ -0,0 +1,166 @@
+package config
+
+import "time"
+
+// 数据库驱动
+const (
+	DatabaseDriverPostgres = "postgres"
+	DatabaseDriverMySQL    = "mysql"
+	DatabaseDriverSQLite   = "sqlite"
+)
+
+// TLS 模式
+const (
+	DatabaseSSLDisable    = "disable"
+	DatabaseSSLAllow      = "allow"
+	DatabaseSSLPrefer     = "prefer"
+	DatabaseSSLRequire    = "require"
+	DatabaseSSLVerifyCA   = "verify-ca"
+	DatabaseSSLVerifyFull = "verify-full"
+)
+
+// DatabasePoolConfig 数据库连接池配置
+type DatabasePoolConfig struct {
+	MaxOpenConns int `mapstructure:"max_open_conns" yaml:"max_open_conns"`
+	MaxIdleConns int `mapstructure:"max_idle_conns" yaml:"max_idle_conns"`
+
+	// 连接最长使用时间
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+	// 连接最长空闲时间
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time" yaml:"conn_max_idle_time"`
+}
+
+// GetDefaultDatabasePoolConfig 获取默认连接池配置
+func GetDefaultDatabasePoolConfig() DatabasePoolConfig {
+	return DatabasePoolConfig{
+		MaxOpenConns:    100,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: time.Hour,
+		ConnMaxIdleTime: 10 * time.Minute,
+	}
+}
+
+// DatabaseRetryConfig 启动时连接数据库的重试配置
+// 每次失败后等待时间翻倍，直到 MaxInterval
+type DatabaseRetryConfig struct {
+	// 最多尝试次数（含第一次），1 表示不重试
+	MaxAttempts     int           `mapstructure:"max_attempts" yaml:"max_attempts"`
+	InitialInterval time.Duration `mapstructure:"initial_interval" yaml:"initial_interval"`
+	MaxInterval     time.Duration `mapstructure:"max_interval" yaml:"max_interval"`
+}
+
+// GetDefaultDatabaseRetryConfig 获取默认连接重试配置
+func GetDefaultDatabaseRetryConfig() DatabaseRetryConfig {
+	return DatabaseRetryConfig{
+		MaxAttempts:     5,
+		InitialInterval: time.Second,
+		MaxInterval:     15 * time.Second,
+	}
+}
+
+// DatabaseReplica 只读副本连接配置，未设置的字段继承主库配置
+// TLS、时区、连接池等其余参数与主库相同
+type DatabaseReplica struct {
+	Host     string `mapstructure:"host" yaml:"host"`
+	Port     string `mapstructure:"port" yaml:"port"`
+	User     string `mapstructure:"user" yaml:"user"`
+	Password string `mapstructure:"password" yaml:"password"`
+	Name     string `mapstructure:"name" yaml:"name"`
+}
+
+// Replica 返回副本的完整连接配置
+func (d Database) Replica(r DatabaseReplica) Database {
+	if r.Host != "" {
+		d.Host = r.Host
+	}
+	if r.Port != "" {
+		d.Port = r.Port
+	}
+	if r.User != "" {
+		d.User = r.User
+	}
+	if r.Password != "" {
+		d.Password = r.Password
+	}
+	if r.Name != "" {
+		d.Name = r.Name
+	}
+	d.Replicas = nil
+	return d
+}
+
+// WithDefaults 为未设置的连接参数填充默认值
+// 直接构造 Database（如测试中使用 SQLite）时由 database.Init 调用
+func (d Database) WithDefaults() Database {
+	if d.SSLMode == "" {
+		d.SSLMode = DatabaseSSLDisable
+	}
+	if d.TimeZone == "" {
+		d.TimeZone = "Asia/Shanghai"
+	}
+	if d.ApplicationName == "" {
+		d.ApplicationName = "go-manage-starter"
+	}
+
+	pool := GetDefaultDatabasePoolConfig()
+	if d.Pool.MaxOpenConns <= 0 {
+		d.Pool.MaxOpenConns = pool.MaxOpenConns
+	}
+	if d.Pool.MaxIdleConns <= 0 {
+		d.Pool.MaxIdleConns = pool.MaxIdleConns
+	}
+	if d.Pool.ConnMaxLifetime <= 0 {
+		d.Pool.ConnMaxLifetime = pool.ConnMaxLifetime
+	}
+	if d.Pool.ConnMaxIdleTime <= 0 {
+		d.Pool.ConnMaxIdleTime = pool.ConnMaxIdleTime
+	}
+
+	if d.ReplicaCheckInterval <= 0 {
+		d.ReplicaCheckInterval = 10 * time.Second
+	}
+
+	retry := GetDefaultDatabaseRetryConfig()
+	if d.ConnectRetry.MaxAttempts <= 0 {
+		d.ConnectRetry.MaxAttempts = retry.MaxAttempts
+	}
+	if d.ConnectRetry.InitialInterval <= 0 {
+		d.ConnectRetry.InitialInterval = retry.InitialInterval
+	}
+	if d.ConnectRetry.MaxInterval <= 0 {
+		d.ConnectRetry.MaxInterval = retry.MaxInterval
+	}
+	return d
+}
+
+// GORM 日志级别
+const (
+	DatabaseLogSilent = "silent" // 不输出任何 SQL 日志
+	DatabaseLogError  = "error"  // 仅输出执行失败的 SQL
+	DatabaseLogWarn   = "warn"   // 额外输出慢查询
+	DatabaseLogInfo   = "info"   // 额外输出 GORM 的提示信息
+	DatabaseLogDebug  = "debug"  // 输出所有 SQL
+)
+
+// DatabaseLogConfig 数据库 SQL 日志配置
+type DatabaseLogConfig struct {
+	// SQL 日志级别: silent, error, warn, info, debug，为空时跟随全局 log_level
+	// 对应名为 gorm 的日志器，运行时可通过管理接口单独调整
+	Level string `mapstructure:"level" yaml:"level"`
+
+	// 慢查询阈值，超过时以 warn 级别记录并计入慢查询指标，0 使用默认值
+	SlowThreshold time.Duration `mapstructure:"slow_threshold" yaml:"slow_threshold"`
+
+	// 是否记录 record not found 错误（默认忽略）
+	LogRecordNotFound bool `mapstructure:"log_record_not_found" yaml:"log_record_not_found"`
+
+	// 是否只记录参数化 SQL，不记录参数值（避免敏感数据写入日志）
+	ParameterizedQueries bool `mapstructure:"parameterized_queries" yaml:"parameterized_queries"`
+}
+
+// GetDefaultDatabaseLogConfig 获取默认数据库日志配置
+func GetDefaultDatabaseLogConfig() DatabaseLogConfig {
+	return DatabaseLogConfig{
+		SlowThreshold: 200 * time.Millisecond,
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import "time"
+
+// ExportConfig 数据导出配置
+type ExportConfig struct {
+	// 导出行数超过该值时转为后台任务，生成完成后下载
+	SyncMaxRows int `mapstructure:"sync_max_rows" yaml:"sync_max_rows"`
+
+	// 后台任务结果文件目录，启动时会清理目录中遗留的任务文件
+	Dir string `mapstructure:"dir" yaml:"dir"`
+
+	// 后台任务结果保留时间
+	JobTTL time.Duration `mapstructure:"job_ttl" yaml:"job_ttl"`
+
+	// 同时运行的后台任务数
+	MaxConcurrentJobs int `mapstructure:"max_concurrent_jobs" yaml:"max_concurrent_jobs"`
+}
+
+// GetDefaultExportConfig 获取默认导出配置
+func GetDefaultExportConfig() ExportConfig {
+	return ExportConfig{
+		SyncMaxRows:       5000,
+		Dir:               "exports",
+		JobTTL:            24 * time.Hour,
+		MaxConcurrentJobs: 2,
+	}
+}
+
+// withDefaults 为未设置的字段填充默认值
+func (c ExportConfig) withDefaults() ExportConfig {
+	defaults := GetDefaultExportConfig()
+	if c.SyncMaxRows <= 0 {
+		c.SyncMaxRows = defaults.SyncMaxRows
+	}
+	if c.Dir == "" {
+		c.Dir = defaults.Dir
+	}
+	if c.JobTTL <= 0 {
+		c.JobTTL = defaults.JobTTL
+	}
+	if c.MaxConcurrentJobs <= 0 {
+		c.MaxConcurrentJobs = defaults.MaxConcurrentJobs
+	}
+	return c
+}
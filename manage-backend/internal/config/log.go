@@ -0,0 +1,53 @@
+package config
+
+import "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+
+// LogConfig 日志输出配置（日志级别使用顶层的 log_level）
+type LogConfig struct {
+	// 输出方式: stdout(默认), file(滚动文件), both(同时输出)
+	Output string        `mapstructure:"output" yaml:"output"`
+	File   LogFileConfig `mapstructure:"file" yaml:"file"`
+
+	// 敏感字段脱敏
+	Redact LogRedactConfig `mapstructure:"redact" yaml:"redact"`
+}
+
+// LogFileConfig 滚动日志文件配置
+type LogFileConfig struct {
+	Path       string `mapstructure:"path" yaml:"path"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb" yaml:"max_size_mb"`
+	MaxBackups int    `mapstructure:"max_backups" yaml:"max_backups"`
+	MaxAgeDays int    `mapstructure:"max_age_days" yaml:"max_age_days"`
+	Compress   bool   `mapstructure:"compress" yaml:"compress"`
+}
+
+// LogRedactConfig 日志脱敏配置
+type LogRedactConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// 需要脱敏的字段名，为空时使用 logger.DefaultRedactFields
+	Fields []string `mapstructure:"fields" yaml:"fields"`
+}
+
+// LoggerOptions 将配置转换为日志器选项
+func (c *Config) LoggerOptions() logger.Options {
+	opts := logger.Options{
+		Level:  c.LogLevel,
+		Output: c.Log.Output,
+		File: logger.FileOptions{
+			Path:       c.Log.File.Path,
+			MaxSizeMB:  c.Log.File.MaxSizeMB,
+			MaxBackups: c.Log.File.MaxBackups,
+			MaxAgeDays: c.Log.File.MaxAgeDays,
+			Compress:   c.Log.File.Compress,
+		},
+	}
+
+	switch {
+	case !c.Log.Redact.Enabled:
+		opts.RedactFields = []string{}
+	case len(c.Log.Redact.Fields) > 0:
+		opts.RedactFields = c.Log.Redact.Fields
+	}
+	return opts
+}
@@ -11,14 +11,20 @@ func LogConfigDetails(cfg *Config) {
 	logger.Info("配置信息", 
 		zap.String("环境", cfg.Environment),
 		zap.String("端口", cfg.Port),
-		zap.String("日志级别", cfg.LogLevel))
+		zap.Duration("写超时", cfg.Server.WriteTimeout),
+		zap.Duration("排空等待时间", cfg.Server.DrainPeriod),
+		zap.String("日志级别", cfg.LogLevel),
+		zap.String("日志输出", cfg.Log.Output),
+		zap.Bool("日志脱敏", cfg.Log.Redact.Enabled))
 	
 	logger.Info("数据库配置",
 		zap.String("用户", cfg.Database.User),
 		zap.String("主机", cfg.Database.Host),
 		zap.String("端口", cfg.Database.Port),
 		zap.String("数据库名", cfg.Database.Name),
-		zap.String("模式", cfg.Database.Schema))
+		zap.String("模式", cfg.Database.Schema),
+		zap.String("SQL日志级别", cfg.Database.Log.Level),
+		zap.Duration("慢查询阈值", cfg.Database.Log.SlowThreshold))
 	
 	redisPassword := "无"
 	if cfg.Redis.Password != "" {
@@ -30,6 +36,22 @@ func LogConfigDetails(cfg *Config) {
 		zap.Int("数据库", cfg.Redis.DB),
 		zap.String("密码", redisPassword))
 	
+	logger.Info("缓存配置",
+		zap.String("驱动", cfg.Cache.Driver),
+		zap.Bool("用户缓存", cfg.Cache.User.Enabled),
+		zap.Duration("用户缓存时间", cfg.Cache.User.TTL))
+
+	logger.Info("会话配置",
+		zap.String("故障策略", cfg.Session.FailureMode),
+		zap.Duration("本地缓存时间", cfg.Session.LocalCacheTTL),
+		zap.Int("熔断阈值", cfg.Session.BreakerThreshold),
+		zap.Duration("熔断冷却时间", cfg.Session.BreakerCooldown))
+
+	logger.Info("链路追踪配置",
+		zap.Bool("启用", cfg.Tracing.Enabled),
+		zap.String("导出方式", cfg.Tracing.Exporter),
+		zap.Float64("采样率", cfg.Tracing.SampleRatio))
+
 	jwtSecret := "未设置"
 	if cfg.JWT.Secret != "" {
 		jwtSecret = "***已设置***"
@@ -0,0 +1,22 @@
+package config
+
+// MetricsConfig 监控指标配置
+type MetricsConfig struct {
+	// 是否启用 Prometheus 指标
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// 指标暴露路径
+	Path string `mapstructure:"path" yaml:"path"`
+
+	// 独立监听地址（如 ":9090"），为空时挂载在业务端口上
+	// 生产环境建议使用独立地址，仅对内网或监控系统开放
+	ListenAddr string `mapstructure:"listen_addr" yaml:"listen_addr"`
+}
+
+// GetDefaultMetricsConfig 获取默认监控指标配置
+func GetDefaultMetricsConfig() MetricsConfig {
+	return MetricsConfig{
+		Enabled: true,
+		Path:    "/metrics",
+	}
+}
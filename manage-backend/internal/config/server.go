@@ -0,0 +1,53 @@
+package config
+
+import "time"
+
+// ServerConfig HTTP 服务配置
+type ServerConfig struct {
+	// 读取整个请求（含请求体）的超时时间
+	ReadTimeout time.Duration `mapstructure:"read_timeout" yaml:"read_timeout"`
+	// 读取请求头的超时时间，防御慢速请求头攻击
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout" yaml:"read_header_timeout"`
+	// 写入响应的超时时间（从读完请求头开始计算）
+	WriteTimeout time.Duration `mapstructure:"write_timeout" yaml:"write_timeout"`
+	// Keep-Alive 连接的空闲超时时间
+	IdleTimeout time.Duration `mapstructure:"idle_timeout" yaml:"idle_timeout"`
+
+	// 收到退出信号后就绪检查返回未就绪并等待该时长，再停止接收新请求
+	DrainPeriod time.Duration `mapstructure:"drain_period" yaml:"drain_period"`
+	// 等待进行中的请求和关闭钩子完成的最长时间
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout" yaml:"shutdown_timeout"`
+}
+
+// GetDefaultServerConfig 获取默认 HTTP 服务配置
+func GetDefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		ReadTimeout:       15 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		DrainPeriod:       0,
+		ShutdownTimeout:   30 * time.Second,
+	}
+}
+
+// withDefaults 为未设置的字段填充默认值（DrainPeriod 为 0 表示不等待）
+func (c ServerConfig) withDefaults() ServerConfig {
+	defaults := GetDefaultServerConfig()
+	if c.ReadTimeout <= 0 {
+		c.ReadTimeout = defaults.ReadTimeout
+	}
+	if c.ReadHeaderTimeout <= 0 {
+		c.ReadHeaderTimeout = defaults.ReadHeaderTimeout
+	}
+	if c.WriteTimeout <= 0 {
+		c.WriteTimeout = defaults.WriteTimeout
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = defaults.IdleTimeout
+	}
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = defaults.ShutdownTimeout
+	}
+	return c
+}
@@ -0,0 +1,68 @@
+package config
+
+import "time"
+
+const (
+	// FailureModeOpen Redis 不可用时放行（无法确认黑名单的 Token 视为有效）
+	FailureModeOpen = "open"
+	// FailureModeClosed Redis 不可用时拒绝（无法确认黑名单的 Token 视为无效）
+	FailureModeClosed = "closed"
+)
+
+// SessionConfig 会话配置
+type SessionConfig struct {
+	// Redis 不可用时的策略: open(放行), closed(拒绝)
+	FailureMode string `mapstructure:"failure_mode" yaml:"failure_mode"`
+
+	// 本地黑名单缓存时间（Redis 不可用时用于兜底判断）
+	LocalCacheTTL time.Duration `mapstructure:"local_cache_ttl" yaml:"local_cache_ttl"`
+
+	// 本地黑名单缓存最大条目数
+	LocalCacheSize int `mapstructure:"local_cache_size" yaml:"local_cache_size"`
+
+	// 连续失败多少次后触发熔断
+	BreakerThreshold int `mapstructure:"breaker_threshold" yaml:"breaker_threshold"`
+
+	// 熔断后多久尝试恢复
+	BreakerCooldown time.Duration `mapstructure:"breaker_cooldown" yaml:"breaker_cooldown"`
+
+	// 清理活跃会话集合中已过期会话的间隔
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval" yaml:"cleanup_interval"`
+}
+
+// GetDefaultSessionConfig 获取默认会话配置
+func GetDefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		FailureMode:      FailureModeOpen,
+		LocalCacheTTL:    30 * time.Second,
+		LocalCacheSize:   10000,
+		BreakerThreshold: 5,
+		BreakerCooldown:  10 * time.Second,
+		CleanupInterval:  10 * time.Minute,
+	}
+}
+
+// WithDefaults 为未设置的字段填充默认值，FailureMode 不是 closed 时按 open 处理
+// 直接构造 SessionConfig（如测试中）时由 service.NewSessionService 调用
+func (c SessionConfig) WithDefaults() SessionConfig {
+	defaults := GetDefaultSessionConfig()
+	if c.FailureMode != FailureModeClosed {
+		c.FailureMode = FailureModeOpen
+	}
+	if c.LocalCacheTTL <= 0 {
+		c.LocalCacheTTL = defaults.LocalCacheTTL
+	}
+	if c.LocalCacheSize <= 0 {
+		c.LocalCacheSize = defaults.LocalCacheSize
+	}
+	if c.BreakerThreshold <= 0 {
+		c.BreakerThreshold = defaults.BreakerThreshold
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = defaults.BreakerCooldown
+	}
+	if c.CleanupInterval <= 0 {
+		c.CleanupInterval = defaults.CleanupInterval
+	}
+	return c
+}
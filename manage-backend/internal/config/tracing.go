@@ -0,0 +1,40 @@
+package config
+
+const (
+	// TracingExporterOTLP 通过 OTLP/HTTP 导出到 Collector、Jaeger、Tempo 等
+	TracingExporterOTLP = "otlp"
+	// TracingExporterStdout 输出到标准输出，便于本地调试
+	TracingExporterStdout = "stdout"
+)
+
+// TracingConfig 链路追踪配置（OpenTelemetry）
+type TracingConfig struct {
+	// 是否启用链路追踪
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+
+	// 服务名称，显示在追踪系统中
+	ServiceName string `mapstructure:"service_name" yaml:"service_name"`
+
+	// 导出方式: otlp, stdout
+	Exporter string `mapstructure:"exporter" yaml:"exporter"`
+
+	// OTLP 接收地址（host:port），为空时使用 OTEL_EXPORTER_OTLP_ENDPOINT 或默认的 localhost:4318
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint"`
+
+	// 是否使用非 TLS 连接
+	Insecure bool `mapstructure:"insecure" yaml:"insecure"`
+
+	// 采样率 (0-1]，上游已采样的请求始终跟随上游决定
+	SampleRatio float64 `mapstructure:"sample_ratio" yaml:"sample_ratio"`
+}
+
+// GetDefaultTracingConfig 获取默认链路追踪配置
+func GetDefaultTracingConfig() TracingConfig {
+	return TracingConfig{
+		Enabled:     false,
+		ServiceName: "go-manage-starter",
+		Exporter:    TracingExporterOTLP,
+		Insecure:    true,
+		SampleRatio: 1,
+	}
+}
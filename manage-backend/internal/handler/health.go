@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/health"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 存活和就绪检查
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Livez godoc
+// @Summary Liveness probe
+// @Description Report whether the process is running; does not check dependencies
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string "服务存活"
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Check database, migrations and cache; only component statuses are returned, details are available to admins at /admin/health
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{} "服务就绪"
+// @Failure 503 {object} map[string]interface{} "依赖不可用"
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.Up() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{
+		"status":     report.Status,
+		"components": report.Summary(),
+	})
+}
+
+// Details godoc
+// @Summary Dependency health details
+// @Description Check all dependencies and return status, latency, version and error of each component (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=health.Report} "检查完成"
+// @Failure 401 {object} utils.APIResponse "未认证"
+// @Failure 403 {object} utils.APIResponse "权限不足"
+// @Router /admin/health [get]
+func (h *HealthHandler) Details(c *gin.Context) {
+	utils.Success(c, h.checker.Check(c.Request.Context()))
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxLogLevelTTL 临时日志级别的最长有效期
+const maxLogLevelTTL = 24 * time.Hour
+
+// LogLevelRequest 调整日志级别请求
+type LogLevelRequest struct {
+	Level  string `json:"level" binding:"required,oneof=debug info warn error" example:"debug"`
+	Logger string `json:"logger" example:"service.user"` // 为空时调整全局级别
+	TTL    string `json:"ttl" example:"10m"`              // 到期自动恢复，为空表示永久生效
+}
+
+// LogLevelHandler 运行时日志级别管理
+type LogLevelHandler struct{}
+
+func NewLogLevelHandler() *LogLevelHandler {
+	return &LogLevelHandler{}
+}
+
+// GetLogLevel godoc
+// @Summary Get log levels
+// @Description Get the global log level and per-logger overrides (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=logger.LevelStatus} "获取成功"
+// @Failure 401 {object} utils.APIResponse "未认证"
+// @Failure 403 {object} utils.APIResponse "权限不足"
+// @Router /admin/log-level [get]
+func (h *LogLevelHandler) GetLogLevel(c *gin.Context) {
+	utils.Success(c, logger.Levels())
+}
+
+// SetLogLevel godoc
+// @Summary Set log level
+// @Description Change the global or a named logger's level at runtime, optionally reverting after a TTL (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body LogLevelRequest true "日志级别"
+// @Success 200 {object} utils.APIResponse{data=logger.LevelStatus} "设置成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未认证"
+// @Failure 403 {object} utils.APIResponse "权限不足"
+// @Router /admin/log-level [put]
+func (h *LogLevelHandler) SetLogLevel(c *gin.Context) {
+	var req LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		d, err := time.ParseDuration(req.TTL)
+		if err != nil || d <= 0 || d > maxLogLevelTTL {
+			utils.BadRequest(c, "ttl 格式错误，需为 0 到 24h 之间的时长，如 10m")
+			return
+		}
+		ttl = d
+	}
+
+	if err := logger.SetLevel(req.Logger, req.Level, ttl); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	logger.InfoContext(c.Request.Context(), "日志级别已调整",
+		zap.String("logger", req.Logger),
+		zap.String("level", req.Level),
+		zap.Duration("ttl", ttl))
+	utils.SuccessWithMessage(c, "日志级别已更新", logger.Levels())
+}
+
+// ResetLogLevel godoc
+// @Summary Reset named log level
+// @Description Remove a named logger's level override so it follows the global level again (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param logger query string true "日志器名称" example(service.user)
+// @Success 200 {object} utils.APIResponse{data=logger.LevelStatus} "重置成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未认证"
+// @Failure 403 {object} utils.APIResponse "权限不足"
+// @Router /admin/log-level [delete]
+func (h *LogLevelHandler) ResetLogLevel(c *gin.Context) {
+	name := c.Query("logger")
+	if name == "" {
+		utils.BadRequest(c, "缺少 logger 参数")
+		return
+	}
+
+	logger.ClearLevel(name)
+	logger.InfoContext(c.Request.Context(), "日志级别覆盖已删除", zap.String("logger", name))
+	utils.SuccessWithMessage(c, "日志级别已重置", logger.Levels())
+}
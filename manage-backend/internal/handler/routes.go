@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"time"
 	"github.com/gin-gonic/gin"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
@@ -9,10 +10,17 @@ import (
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/health"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/jobs"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/lifecycle"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/tracing"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(router *gin.RouterGroup, db *gorm.DB) {
+func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, checker *health.Checker, lc *lifecycle.Manager) {
 	cfg := config.Load()
 	
 	// 使用配置初始化 JWT 管理器
@@ -29,14 +37,45 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB) {
 	
 	jwtManager := auth.NewJWTManager(cfg.JWT.Secret, accessTokenExpire, refreshTokenExpire)
 
-	// 初始化 Redis 客户端
-	redisClient := cache.NewRedisClient(cfg.Redis)
+	// 初始化缓存存储（Redis 或内存）
+	cacheConfig := cfg.Cache
+	cacheStore, err := cache.NewStore(cacheConfig, cfg.Redis)
+	if err != nil {
+		logger.Fatal("缓存存储初始化失败", zap.String("driver", cacheConfig.Driver), zap.Error(err))
+	}
+	lc.OnShutdown("cache", func(ctx context.Context) error {
+		return cacheStore.Close()
+	})
+
+	// 为 Redis 命令添加链路追踪
+	if cfg.Tracing.Enabled {
+		if redisStore, ok := cacheStore.(*cache.RedisClient); ok {
+			if err := tracing.InstrumentRedis(redisStore.GetClient()); err != nil {
+				logger.Warn("Redis 链路追踪初始化失败", zap.Error(err))
+			}
+		}
+	}
+
+	// Redis 加入就绪检查
+	if redisStore, ok := cacheStore.(*cache.RedisClient); ok {
+		checker.Register("redis", func(ctx context.Context) (string, error) {
+			if err := redisStore.Ping(ctx); err != nil {
+				return "", err
+			}
+			return redisStore.ServerVersion(ctx)
+		})
+	}
 
 	// 初始化仓储层
-	userRepo := repository.NewUserRepository(db)
+	var userRepo service.UserRepositoryInterface = repository.NewUserRepository(db)
+	if cacheConfig.User.Enabled {
+		userRepo = repository.NewCachedUserRepository(userRepo, cacheStore, cacheConfig.User.TTL, cacheConfig.User.NegativeTTL)
+	}
 
 	// 初始化服务层
-	sessionService := service.NewSessionService(redisClient, jwtManager)
+	sessionService := service.NewSessionService(cacheStore, jwtManager, cfg.Session)
+	// 定期清理活跃会话集合，避免通过 TTL 过期的会话一直计入活跃会话数
+	lc.OnShutdown("session cleanup", sessionService.StartCleanup())
 	
 	// 验证码配置
 	captchaConfig := service.CaptchaConfig{
@@ -65,12 +104,39 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB) {
 		}
 	}
 	
-	captchaService := service.NewCaptchaService(redisClient.GetClient(), captchaConfig)
+	// 注册缓存和会话相关指标
+	if cfg.Metrics.Enabled {
+		if redisStore, ok := cacheStore.(*cache.RedisClient); ok {
+			metrics.RegisterRedisPoolStats(redisStore.GetClient())
+		}
+		metrics.RegisterActiveSessions(sessionService.CountActiveSessions)
+		metrics.RegisterCircuitBreaker(sessionService.BreakerStats)
+	}
+
+	captchaService := service.NewCaptchaService(cacheStore, captchaConfig)
 	userService := service.NewUserService(userRepo, jwtManager, sessionService, captchaService)
 
+	// 导出任务管理器，大数据量导出在后台生成文件
+	exportConfig := cfg.Export
+	jobManager, err := jobs.NewManager(jobs.Options{
+		Dir:           exportConfig.Dir,
+		TTL:           exportConfig.JobTTL,
+		MaxConcurrent: exportConfig.MaxConcurrentJobs,
+	})
+	if err != nil {
+		logger.Fatal("导出任务管理器初始化失败", zap.String("dir", exportConfig.Dir), zap.Error(err))
+	}
+	lc.OnShutdown("export jobs", jobManager.Shutdown)
+	exportService := service.NewUserExportService(userRepo, jobManager, exportConfig.SyncMaxRows)
+	importService := service.NewUserImportService(userRepo)
+
 	// 初始化处理器
 	userHandler := NewUserHandler(userService)
+	exportHandler := NewUserExportHandler(exportService)
+	importHandler := NewUserImportHandler(importService)
 	captchaHandler := NewCaptchaHandler(captchaService)
+	logLevelHandler := NewLogLevelHandler()
+	healthHandler := NewHealthHandler(checker)
 
 
 	// 用户可用性检查路由（无需认证）
@@ -107,9 +173,23 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB) {
 			users.PUT("/profile", userHandler.UpdateProfile)
 			users.GET("", userHandler.ListUsers)
 			users.POST("", userHandler.CreateUser)
+			users.GET("/export", middleware.RequireRole("admin"), exportHandler.ExportUsers)
+			users.GET("/exports/:id", middleware.RequireRole("admin"), exportHandler.GetExportJob)
+			users.GET("/exports/:id/download", middleware.RequireRole("admin"), exportHandler.DownloadExportJob)
+			users.POST("/import", middleware.RequireRole("admin"), importHandler.ImportUsers)
 			users.GET("/:id", userHandler.GetUser)
 			users.PUT("/:id", userHandler.UpdateUser)
 			users.DELETE("/:id", userHandler.DeleteUser)
 		}
+
+		// 管理员路由
+		admin := protected.Group("/admin")
+		admin.Use(middleware.RequireRole("admin"))
+		{
+			admin.GET("/log-level", logLevelHandler.GetLogLevel)
+			admin.PUT("/log-level", logLevelHandler.SetLogLevel)
+			admin.DELETE("/log-level", logLevelHandler.ResetLogLevel)
+			admin.GET("/health", healthHandler.Details)
+		}
 	}
 }
@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/query"
 )
 
 type UserHandler struct {
@@ -40,7 +45,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Register(&req)
+	user, err := h.userService.Register(c.Request.Context(), &req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    http.StatusBadRequest,
@@ -164,7 +169,7 @@ func (h *UserHandler) Logout(c *gin.Context) {
 // @Router /users/profile [get]
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID := c.GetUint("user_id")
-	user, err := h.userService.GetByID(userID)
+	user, err := h.userService.GetByID(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"code":    http.StatusNotFound,
@@ -202,7 +207,7 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Update(userID, &req)
+	user, err := h.userService.Update(c.Request.Context(), userID, &req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -213,12 +218,22 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 // ListUsers godoc
 // @Summary List users
-// @Description Get list of users with pagination (需要认证)
+// @Description Get list of users with pagination, filtering and sorting. In cursor mode the pagination object is utils.CursorPaginationMeta (需要认证)
 // @Tags users
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
+// @Param username query string false "Username substring (case-insensitive)"
+// @Param email query string false "Email substring (case-insensitive)"
+// @Param role query string false "Role, comma-separated for multiple"
+// @Param status query string false "Status, comma-separated for multiple"
+// @Param created_from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created before (RFC3339, or YYYY-MM-DD inclusive)"
+// @Param sort query string false "Sort fields, prefix - for descending, e.g. -created_at,username" default(id)
+// @Param mode query string false "Pagination mode: page (default) or cursor"
+// @Param cursor query string false "Cursor from next_cursor/prev_cursor of the previous response (implies mode=cursor)"
+// @Param count query string false "Total count in cursor mode: exact (default), estimate or none"
 // @Success 200 {object} utils.PaginatedResponse{data=[]model.UserResponse} "获取成功"
 // @Failure 400 {object} utils.APIResponse "请求参数错误"
 // @Failure 401 {object} utils.APIResponse "未授权"
@@ -242,9 +257,25 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		pageSize = 50
 	}
 
+	spec, err := userListSpec(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	// 游标分页：不使用页码，翻页开销与页数无关
+	if c.Query("mode") == "cursor" || c.Query("cursor") != "" {
+		h.listUsersByCursor(c, pageSize, spec)
+		return
+	}
+
 	// 调用服务层的 List 方法
-	users, total, err := h.userService.List(page, pageSize)
+	users, total, err := h.userService.List(c.Request.Context(), page, pageSize, spec)
 	if err != nil {
+		if errors.Is(err, query.ErrInvalidSpec) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
 		utils.InternalServerError(c, "failed to get user list")
 		return
 	}
@@ -261,6 +292,111 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	utils.PaginatedSuccess(c, users, pagination)
 }
 
+// listUsersByCursor 游标分页模式的用户列表
+func (h *UserHandler) listUsersByCursor(c *gin.Context, pageSize int, spec query.Spec) {
+	count, err := query.ParseCountMode(c.Query("count"))
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	page, err := h.userService.ListCursor(c.Request.Context(), query.CursorRequest{
+		Limit:  pageSize,
+		Cursor: c.Query("cursor"),
+		Count:  count,
+	}, spec)
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidSpec) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, "failed to get user list")
+		return
+	}
+
+	users := page.Items
+	if users == nil {
+		users = []model.User{}
+	}
+	utils.CursorPaginatedSuccess(c, users, utils.CursorPaginationMeta{
+		PageSize:       pageSize,
+		Total:          page.Total,
+		NextCursor:     page.NextCursor,
+		PrevCursor:     page.PrevCursor,
+		TotalEstimated: page.TotalEstimated,
+	})
+}
+
+// userListSpec 将用户列表的查询参数转换为查询规格，字段白名单由 repository.UserListSchema 校验
+func userListSpec(c *gin.Context) (query.Spec, error) {
+	var spec query.Spec
+
+	for _, field := range []string{"username", "email"} {
+		if v := strings.TrimSpace(c.Query(field)); v != "" {
+			spec.Where(field, query.OpContains, v)
+		}
+	}
+	for _, field := range []string{"role", "status"} {
+		values := splitList(c.Query(field))
+		switch len(values) {
+		case 0:
+		case 1:
+			spec.Where(field, query.OpEq, values[0])
+		default:
+			spec.Where(field, query.OpIn, values)
+		}
+	}
+
+	if v := c.Query("created_from"); v != "" {
+		from, _, err := parseTimeParam(v)
+		if err != nil {
+			return spec, fmt.Errorf("invalid created_from: %w", err)
+		}
+		spec.Where("created_at", query.OpGte, from)
+	}
+	if v := c.Query("created_to"); v != "" {
+		to, dateOnly, err := parseTimeParam(v)
+		if err != nil {
+			return spec, fmt.Errorf("invalid created_to: %w", err)
+		}
+		if dateOnly {
+			// 只有日期时包含当天
+			spec.Where("created_at", query.OpLt, to.AddDate(0, 0, 1))
+		} else {
+			spec.Where("created_at", query.OpLte, to)
+		}
+	}
+
+	sorts, err := query.ParseSort(c.Query("sort"))
+	if err != nil {
+		return spec, err
+	}
+	spec.Sorts = sorts
+	return spec, nil
+}
+
+// splitList 解析逗号分隔的参数值，忽略空值
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseTimeParam 解析 RFC3339 时间或 YYYY-MM-DD 日期（按本地时区），dateOnly 表示只有日期
+func parseTimeParam(s string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.Parse(time.RFC3339, s); err == nil {
+		return t, false, nil
+	}
+	if t, err = time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, errors.New("expected RFC3339 time or YYYY-MM-DD date")
+}
+
 // CreateUser godoc
 // @Summary Create a new user
 // @Description Create a new user (admin only)
@@ -281,7 +417,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Register(&req)
+	user, err := h.userService.Register(c.Request.Context(), &req)
 	if err != nil {
 		if err.Error() == "username already exists" || err.Error() == "email already exists" {
 			utils.BadRequest(c, err.Error())
@@ -323,7 +459,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Update(uint(id), &req)
+	user, err := h.userService.Update(c.Request.Context(), uint(id), &req)
 	if err != nil {
 		if err.Error() == "user not found" {
 			utils.NotFound(c, "user not found")
@@ -353,7 +489,7 @@ func (h *UserHandler) CheckUsernameAvailable(c *gin.Context) {
 		return
 	}
 
-	available, err := h.userService.CheckUsernameAvailable(username)
+	available, err := h.userService.CheckUsernameAvailable(c.Request.Context(), username)
 	if err != nil {
 		utils.InternalServerError(c, "failed to check username availability")
 		return
@@ -383,7 +519,7 @@ func (h *UserHandler) CheckEmailAvailable(c *gin.Context) {
 		return
 	}
 
-	available, err := h.userService.CheckEmailAvailable(email)
+	available, err := h.userService.CheckEmailAvailable(c.Request.Context(), email)
 	if err != nil {
 		utils.InternalServerError(c, "failed to check email availability")
 		return
@@ -420,7 +556,7 @@ func (h *UserHandler) CheckUserDataAvailability(c *gin.Context) {
 		return
 	}
 
-	response, err := h.userService.CheckUserDataAvailability(&req)
+	response, err := h.userService.CheckUserDataAvailability(c.Request.Context(), &req)
 	if err != nil {
 		utils.InternalServerError(c, "failed to check data availability")
 		return
@@ -450,7 +586,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	err = h.userService.Delete(uint(id))
+	err = h.userService.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		if err.Error() == "user not found" {
 			utils.NotFound(c, "user not found")
@@ -484,7 +620,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetByID(uint(id))
+	user, err := h.userService.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		if err.Error() == "user not found" {
 			utils.NotFound(c, "user not found")
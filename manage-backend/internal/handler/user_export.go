@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/export"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/jobs"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/query"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type UserExportHandler struct {
+	exportService *service.UserExportService
+}
+
+func NewUserExportHandler(exportService *service.UserExportService) *UserExportHandler {
+	return &UserExportHandler{exportService: exportService}
+}
+
+// ExportUsers godoc
+// @Summary Export users
+// @Description Export the user list as CSV or XLSX with the same filters as the list endpoint. Small results are streamed directly; large results (or async=true) start a background job and return 202 (需要管理员权限)
+// @Tags users
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "Export format: csv (default) or xlsx"
+// @Param columns query string false "Comma-separated columns: id,username,email,role,status,created_at,updated_at (default all)"
+// @Param lang query string false "Header language: zh or en (defaults to Accept-Language, then zh)"
+// @Param async query bool false "Always run as a background job"
+// @Param username query string false "Username substring (case-insensitive)"
+// @Param email query string false "Email substring (case-insensitive)"
+// @Param role query string false "Role, comma-separated for multiple"
+// @Param status query string false "Status, comma-separated for multiple"
+// @Param created_from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created before (RFC3339, or YYYY-MM-DD inclusive)"
+// @Param sort query string false "Sort fields, prefix - for descending, e.g. -created_at,username" default(id)
+// @Success 200 {file} file "导出文件"
+// @Success 202 {object} utils.APIResponse{data=jobs.Job} "已创建后台导出任务"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 403 {object} utils.APIResponse "权限不足"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Failure 503 {object} utils.APIResponse "服务正在关闭"
+// @Router /users/export [get]
+func (h *UserExportHandler) ExportUsers(c *gin.Context) {
+	spec, err := userListSpec(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+	req := service.UserExportRequest{
+		Spec:    spec,
+		Format:  format,
+		Columns: splitList(c.Query("columns")),
+		Lang:    exportLang(c),
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.exportService.Start(ctx, c.GetUint("user_id"), req, c.Query("async") == "true")
+	if err != nil {
+		switch {
+		case errors.Is(err, query.ErrInvalidSpec), errors.Is(err, service.ErrInvalidExport):
+			utils.BadRequest(c, err.Error())
+		case errors.Is(err, jobs.ErrShuttingDown):
+			utils.ServiceUnavailable(c, "server is shutting down")
+		default:
+			utils.InternalServerError(c, "failed to export users")
+		}
+		return
+	}
+	if result.Job != nil {
+		utils.Accepted(c, result.Job)
+		return
+	}
+
+	// 同步导出：响应头发送后无法再返回 JSON 错误，失败时只能中断输出并记录日志
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", attachmentDisposition(service.ExportFileName(format)))
+	c.Status(http.StatusOK)
+	if err := h.exportService.Export(ctx, c.Writer, req, nil); err != nil {
+		logger.Error("用户导出输出失败", zap.Error(err))
+		c.Abort()
+	}
+}
+
+// GetExportJob godoc
+// @Summary Get export job
+// @Description Get the status and progress of a background export job submitted by the current user (需要管理员权限)
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Job ID"
+// @Success 200 {object} utils.APIResponse{data=jobs.Job} "获取成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 403 {object} utils.APIResponse "权限不足"
+// @Failure 404 {object} utils.APIResponse "任务不存在或已过期"
+// @Router /users/exports/{id} [get]
+func (h *UserExportHandler) GetExportJob(c *gin.Context) {
+	job, err := h.exportService.GetJob(c.Param("id"), c.GetUint("user_id"))
+	if err != nil {
+		utils.NotFound(c, "export job not found")
+		return
+	}
+	utils.Success(c, job)
+}
+
+// DownloadExportJob godoc
+// @Summary Download export result
+// @Description Download the file generated by a finished background export job (需要管理员权限)
+// @Tags users
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Job ID"
+// @Success 200 {file} file "导出文件"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 403 {object} utils.APIResponse "权限不足"
+// @Failure 404 {object} utils.APIResponse "任务不存在或已过期"
+// @Failure 409 {object} utils.APIResponse "任务尚未完成"
+// @Router /users/exports/{id}/download [get]
+func (h *UserExportHandler) DownloadExportJob(c *gin.Context) {
+	job, file, err := h.exportService.OpenJob(c.Param("id"), c.GetUint("user_id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, jobs.ErrNotReady):
+			utils.Conflict(c, fmt.Sprintf("export job is %s", job.Status))
+		case errors.Is(err, jobs.ErrNotFound):
+			utils.NotFound(c, "export job not found")
+		default:
+			// 结果文件已被清理等情况
+			utils.NotFound(c, "export file not found")
+		}
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", job.ContentType)
+	c.Header("Content-Disposition", attachmentDisposition(job.FileName))
+	modTime := job.CreatedAt
+	if job.DoneAt != nil {
+		modTime = *job.DoneAt
+	}
+	http.ServeContent(c.Writer, c.Request, job.FileName, modTime, file)
+}
+
+// exportLang 导出表头语言：优先使用 lang 参数，其次根据 Accept-Language 判断
+func exportLang(c *gin.Context) string {
+	if lang := c.Query("lang"); lang != "" {
+		return strings.ToLower(lang)
+	}
+	accept := strings.ToLower(c.GetHeader("Accept-Language"))
+	if strings.HasPrefix(accept, "en") {
+		return "en"
+	}
+	return "zh"
+}
+
+// attachmentDisposition 生成下载文件的 Content-Disposition
+func attachmentDisposition(fileName string) string {
+	return fmt.Sprintf("attachment; filename=%q; filename*=UTF-8''%s", fileName, url.PathEscape(fileName))
+}
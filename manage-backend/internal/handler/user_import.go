@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/export"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize 导入文件大小上限
+const maxImportFileSize = 5 << 20
+
+type UserImportHandler struct {
+	importService *service.UserImportService
+}
+
+func NewUserImportHandler(importService *service.UserImportService) *UserImportHandler {
+	return &UserImportHandler{importService: importService}
+}
+
+// ImportUsers godoc
+// @Summary Import users
+// @Description Bulk create users from a CSV or XLSX file. The first row is the header (username, email, password, role, status; the Chinese or English export headers are also accepted). By default only validates and returns a per-row report; with commit=true the users are created in one transaction when every row is valid (需要管理员权限)
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV or XLSX file, at most 1000 rows (200 rows with commit=true)"
+// @Param format query string false "File format: csv or xlsx (defaults to the file extension)"
+// @Param commit query bool false "Create the users when all rows are valid"
+// @Param generate_passwords query bool false "Generate random passwords for rows without a password; returned once in the commit report"
+// @Success 200 {object} utils.APIResponse{data=service.UserImportReport} "校验或导入完成"
+// @Failure 400 {object} utils.APIResponse "文件不合法"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 403 {object} utils.APIResponse "权限不足"
+// @Failure 413 {object} utils.APIResponse "文件过大"
+// @Failure 422 {object} utils.APIResponse{data=service.UserImportReport} "存在错误行，未创建任何用户"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/import [post]
+func (h *UserImportHandler) ImportUsers(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, utils.APIResponse{
+				Code:    http.StatusRequestEntityTooLarge,
+				Message: "file too large",
+				Error:   "import file must be at most 5MB",
+			})
+			return
+		}
+		utils.BadRequest(c, "file is required")
+		return
+	}
+
+	var format export.Format
+	if f := c.Query("format"); f != "" {
+		format, err = export.ParseFormat(f)
+	} else {
+		format, err = export.FormatFromFileName(fileHeader.Filename)
+	}
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequest(c, "failed to read file")
+		return
+	}
+	defer file.Close()
+
+	commit := c.Query("commit") == "true"
+	report, err := h.importService.Import(c.Request.Context(), service.UserImportRequest{
+		File:              file,
+		Format:            format,
+		Commit:            commit,
+		GeneratePasswords: c.Query("generate_passwords") == "true",
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidImport) {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+		utils.InternalServerError(c, "failed to import users")
+		return
+	}
+
+	switch {
+	case commit && report.Invalid > 0:
+		c.JSON(http.StatusUnprocessableEntity, utils.APIResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "import has invalid rows, no users were created",
+			Data:    report,
+		})
+	case commit:
+		utils.SuccessWithMessage(c, "users imported", report)
+	default:
+		utils.SuccessWithMessage(c, "validation completed", report)
+	}
+}
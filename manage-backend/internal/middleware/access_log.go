@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AccessLog 结构化访问日志中间件，替代 gin.Logger()
+// 通过 pkg/logger 输出 JSON 日志，自动带上请求ID、用户（由认证中间件写入 context）和链路追踪 ID；
+// 5xx 记为 Error，4xx 记为 Warn，其余记为 Info
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		status := c.Writer.Status()
+		bytes := c.Writer.Size()
+		if bytes < 0 {
+			bytes = 0
+		}
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+			zap.String("path", path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Int("bytes", bytes),
+			zap.String("user_agent", c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.ByType(gin.ErrorTypePrivate).String()))
+		}
+
+		ctx := c.Request.Context()
+		switch {
+		case status >= http.StatusInternalServerError:
+			logger.ErrorContext(ctx, "HTTP 请求", fields...)
+		case status >= http.StatusBadRequest:
+			logger.WarnContext(ctx, "HTTP 请求", fields...)
+		default:
+			logger.InfoContext(ctx, "HTTP 请求", fields...)
+		}
+	}
+}
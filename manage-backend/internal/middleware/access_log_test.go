@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLogUserFieldsFromContext(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	original := logger.Logger
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = original })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AccessLog())
+	router.GET("/profile", func(c *gin.Context) {
+		// 与 JWTAuth 一致：同时写入 gin.Context 和 request context
+		c.Set("user_id", uint(7))
+		c.Request = c.Request.WithContext(logger.WithUser(c.Request.Context(), 7, "alice"))
+		c.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/profile", nil))
+
+	require.Len(t, logs.All(), 1)
+	keys := make(map[string]int)
+	for _, f := range logs.All()[0].Context {
+		keys[f.Key]++
+	}
+	assert.Equal(t, 1, keys["user_id"], "user_id must appear once")
+	assert.Equal(t, 1, keys["username"])
+	assert.Equal(t, 1, keys["status"])
+}
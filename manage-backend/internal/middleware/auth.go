@@ -2,19 +2,23 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
 )
 
 // SessionServiceInterface 会话服务接口（用于中间件扩展）
 // 提供了 Token 黑名单检测和用户活跃状态更新的方法
 type SessionServiceInterface interface {
-	IsTokenBlacklisted(ctx context.Context, jti string) bool // 判断 Token 是否在黑名单中
-	UpdateLastActivity(ctx context.Context, userID uint) error // 更新用户最后活跃时间
-	SetUserActive(ctx context.Context, userID uint) error      // 设置用户为活跃状态
+	IsTokenBlacklisted(ctx context.Context, jti string) (bool, error) // 判断 Token 是否在黑名单中，会话存储不可用时返回 error
+	UpdateLastActivity(ctx context.Context, userID uint) error         // 更新用户最后活跃时间
+	SetUserActive(ctx context.Context, userID uint) error              // 设置用户为活跃状态
 }
 
 // JWTAuth 基础 JWT 鉴权中间件（不包含会话服务）
@@ -53,18 +57,28 @@ func JWTAuthWithSession(jwtManager *auth.JWTManager, sessionService SessionServi
 
 		// 如果启用了会话服务，则检查黑名单和更新用户状态
 		if sessionService != nil {
-			ctx := context.Background()
+			ctx := c.Request.Context()
 
-			// 检查 Token 是否已被拉黑
-			if sessionService.IsTokenBlacklisted(ctx, claims.JTI) {
+			// 检查 Token 是否已被拉黑（fail-closed 模式下会话存储不可用会返回错误）
+			blacklisted, err := sessionService.IsTokenBlacklisted(ctx, claims.JTI)
+			if err != nil {
+				utils.ServiceUnavailable(c, "会话服务暂不可用，请稍后重试")
+				c.Abort()
+				return
+			}
+			if blacklisted {
 				utils.Unauthorized(c, "Token 已被吊销")
 				c.Abort()
 				return
 			}
 
-			// 更新用户活跃状态
-			sessionService.UpdateLastActivity(ctx, claims.UserID)
-			sessionService.SetUserActive(ctx, claims.UserID)
+			// 更新用户活跃状态（失败不影响本次请求）
+			if err := sessionService.UpdateLastActivity(ctx, claims.UserID); err != nil {
+				logActivityError("更新最后活跃时间失败", claims.UserID, err)
+			}
+			if err := sessionService.SetUserActive(ctx, claims.UserID); err != nil {
+				logActivityError("设置用户活跃状态失败", claims.UserID, err)
+			}
 		}
 
 		// 将用户信息保存到 Gin Context 中，后续 Handler 可以直接使用
@@ -74,6 +88,9 @@ func JWTAuthWithSession(jwtManager *auth.JWTManager, sessionService SessionServi
 		c.Set("jti", claims.JTI)
 		c.Set("access_token", tokenString)
 
+		// 写入 request context，后续日志自动带上用户信息
+		c.Request = c.Request.WithContext(logger.WithUser(c.Request.Context(), claims.UserID, claims.Username))
+
 		c.Next()
 	})
 }
@@ -110,3 +127,13 @@ func RefreshTokenAuth(jwtManager *auth.JWTManager) gin.HandlerFunc {
 		c.Next()
 	})
 }
+
+// logActivityError 记录用户活跃状态更新失败
+// 熔断期间每个请求都会失败，降为 Debug 级别避免刷屏
+func logActivityError(msg string, userID uint, err error) {
+	if errors.Is(err, cache.ErrCircuitOpen) {
+		logger.Debug(msg, zap.Uint("user_id", userID), zap.Error(err))
+		return
+	}
+	logger.Warn(msg, zap.Uint("user_id", userID), zap.Error(err))
+}
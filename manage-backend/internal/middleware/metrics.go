@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute 未匹配到路由的请求统一归为一个标签，避免任意路径产生大量时间序列
+const unmatchedRoute = "unmatched"
+
+// Metrics HTTP 请求指标中间件
+// 按路由模板（c.FullPath()）、请求方法和状态码统计请求数和耗时
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader 请求ID的 HTTP 头
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey 请求ID在 gin.Context 中的键
+	RequestIDKey = "request_id"
+
+	// maxRequestIDLength 客户端传入请求ID的最大长度
+	maxRequestIDLength = 128
+)
+
+// RequestID 请求ID中间件
+// 优先使用客户端或网关传入的 X-Request-ID，不合法或未传入时生成新的ID。
+// 请求ID会写入响应头、gin.Context 和 request context，供日志关联使用。
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// validRequestID 校验外部传入的请求ID，防止日志注入和超长值
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID 生成 32 位十六进制随机ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
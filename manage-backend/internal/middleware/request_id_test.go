@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRequestIDRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, logger.RequestIDFromContext(c.Request.Context()))
+	})
+	return router
+}
+
+func TestRequestID(t *testing.T) {
+	router := setupRequestIDRouter()
+
+	tests := []struct {
+		name     string
+		header   string
+		wantEcho bool
+	}{
+		{"使用传入的请求ID", "abc-123_DEF", true},
+		{"未传入时生成", "", false},
+		{"非法字符时重新生成", "bad id\nINJECT", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(RequestIDHeader)
+			assert.NotEmpty(t, got)
+			assert.Equal(t, got, rec.Body.String(), "响应头和 context 中的请求ID应一致")
+			if tt.wantEcho {
+				assert.Equal(t, tt.header, got)
+			} else {
+				assert.Len(t, got, 32)
+			}
+		})
+	}
+}
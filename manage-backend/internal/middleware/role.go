@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole 要求当前用户具有指定角色之一，需放在 JWTAuth/JWTAuthWithSession 之后
+func RequireRole(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		allowed[role] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		roleStr, _ := role.(string)
+		if _, ok := allowed[roleStr]; !ok {
+			utils.Forbidden(c, "权限不足")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
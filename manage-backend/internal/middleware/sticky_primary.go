@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
+	"github.com/gin-gonic/gin"
+)
+
+// StickyPrimary 写后读主库中间件
+// 配置了数据库只读副本时使用：同一请求中执行过写操作后，后续读操作使用主库，避免读到副本上尚未同步的数据
+func StickyPrimary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(database.WithStickyPrimary(c.Request.Context()))
+		c.Next()
+	}
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Role 角色
+// 用户通过 User.Role 保存角色名称，这里维护角色的展示名称和说明
+type Role struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	Name        string    `json:"name" gorm:"uniqueIndex;size:50;not null"`
+	DisplayName string    `json:"display_name" gorm:"size:100"`
+	Description string    `json:"description" gorm:"size:255"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
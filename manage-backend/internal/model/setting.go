@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Setting 系统设置（键值对）
+type Setting struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	Key         string    `json:"key" gorm:"uniqueIndex;size:100;not null"`
+	Value       string    `json:"value" gorm:"type:text"`
+	Description string    `json:"description" gorm:"size:255"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
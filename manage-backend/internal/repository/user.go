@@ -1,10 +1,29 @@
 package repository
 
 import (
+	"context"
+	"strings"
+
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/query"
 	"gorm.io/gorm"
 )
 
+// UserListSchema 用户列表允许的过滤和排序字段
+var UserListSchema = query.Schema{
+	Fields: map[string]query.Field{
+		"id":         {Ops: []query.Op{query.OpEq, query.OpIn}, Sortable: true},
+		"username":   {Ops: []query.Op{query.OpEq, query.OpContains}, Sortable: true},
+		"email":      {Ops: []query.Op{query.OpEq, query.OpContains}, Sortable: true},
+		"role":       {Ops: []query.Op{query.OpEq, query.OpIn}, Sortable: true},
+		"status":     {Ops: []query.Op{query.OpEq, query.OpIn}, Sortable: true},
+		"created_at": {Ops: []query.Op{query.OpGte, query.OpLt, query.OpLte, query.OpGt}, Sortable: true},
+		"updated_at": {Sortable: true},
+	},
+	DefaultSort: []query.Sort{{Field: "id"}},
+	TieBreaker:  "id",
+}
+
 // UserRepository 用户数据仓库
 // 封装对 User 模型的所有数据库操作
 type UserRepository struct {
@@ -17,30 +36,46 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 }
 
 // Create 新增用户
-// 参数: user - 用户对象
+// 参数: ctx - 请求上下文, user - 用户对象
 // 返回: error - 操作是否成功
-func (r *UserRepository) Create(user *model.User) error {
-	return r.db.Create(user).Error
+func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+// CreateBatch 在一个事务中分批新增用户，任一批失败时全部回滚
+// 参数: ctx - 请求上下文, users - 待创建的用户（成功后回填 ID）, batchSize - 每批插入的数量
+// 返回: error - 创建是否成功
+func (r *UserRepository) CreateBatch(ctx context.Context, users []*model.User, batchSize int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(users, batchSize).Error
+	})
 }
 
 // GetByID 根据 ID 获取用户
-// 参数: id - 用户ID
+// 参数: ctx - 请求上下文, id - 用户ID
 // 返回: *model.User - 用户对象, error - 查询是否成功
-func (r *UserRepository) GetByID(id uint) (*model.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	err := r.db.First(&user, id).Error
+	err := r.db.WithContext(ctx).First(&user, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
+// GetCredentials 根据用户名获取用户及密码哈希，用于登录校验，不经过缓存
+// 参数: ctx - 请求上下文, username - 用户名
+// 返回: *model.User - 用户对象（包含密码哈希）, error - 查询是否成功
+func (r *UserRepository) GetCredentials(ctx context.Context, username string) (*model.User, error) {
+	return r.GetByUsername(ctx, username)
+}
+
 // GetByUsername 根据用户名获取用户
-// 参数: username - 用户名
+// 参数: ctx - 请求上下文, username - 用户名
 // 返回: *model.User - 用户对象, error - 查询是否成功
-func (r *UserRepository) GetByUsername(username string) (*model.User, error) {
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	err := r.db.Where("username = ?", username).First(&user).Error
+	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -48,81 +83,165 @@ func (r *UserRepository) GetByUsername(username string) (*model.User, error) {
 }
 
 // GetByEmail 根据邮箱获取用户
-// 参数: email - 邮箱地址
+// 参数: ctx - 请求上下文, email - 邮箱地址
 // 返回: *model.User - 用户对象, error - 查询是否成功
-func (r *UserRepository) GetByEmail(email string) (*model.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-// Update 更新用户信息
-// 参数: user - 用户对象（需包含ID）
+// userUpdateColumns Update 写入的列，密码等其他列不会被修改
+var userUpdateColumns = []string{"username", "email", "role", "status"}
+
+// Update 更新用户信息，只写入 userUpdateColumns 中的列（updated_at 自动更新）
+// 参数: ctx - 请求上下文, user - 用户对象（需包含ID）
 // 返回: error - 操作是否成功
-func (r *UserRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
+	return r.db.WithContext(ctx).Model(user).Select(userUpdateColumns).Updates(user).Error
 }
 
 // Delete 删除用户
-// 参数: id - 用户ID
+// 参数: ctx - 请求上下文, id - 用户ID
 // 返回: error - 操作是否成功
-func (r *UserRepository) Delete(id uint) error {
-	return r.db.Delete(&model.User{}, id).Error
+func (r *UserRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.User{}, id).Error
 }
 
 // List 分页获取用户列表
-// 参数: offset - 偏移量, limit - 每页数量
-// 返回: []model.User - 用户列表, int64 - 总记录数, error - 查询是否成功
-func (r *UserRepository) List(offset, limit int) ([]model.User, int64, error) {
+// 参数: ctx - 请求上下文, offset - 偏移量, limit - 每页数量, spec - 过滤和排序条件（字段须在 UserListSchema 中）
+// 返回: []model.User - 用户列表, int64 - 总记录数, error - 查询是否成功，条件不合法时为 query.ErrInvalidSpec
+func (r *UserRepository) List(ctx context.Context, offset, limit int, spec query.Spec) ([]model.User, int64, error) {
 	var users []model.User
 	var total int64
 
 	// 获取总数
-	err := r.db.Model(&model.User{}).Count(&total).Error
+	countDB, err := UserListSchema.Filter(r.db.WithContext(ctx).Model(&model.User{}), spec)
 	if err != nil {
 		return nil, 0, err
 	}
+	if err := countDB.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 
 	// 分页查询
-	err = r.db.Offset(offset).Limit(limit).Find(&users).Error
+	listDB, err := UserListSchema.Apply(r.db.WithContext(ctx), spec)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = listDB.Offset(offset).Limit(limit).Find(&users).Error
 	return users, total, err
 }
 
+// ListCursor 按游标分页获取用户列表，适用于数据量较大、不需要跳页的场景
+// 参数: ctx - 请求上下文, req - 游标和每页数量, spec - 过滤和排序条件（字段须在 UserListSchema 中）
+// 返回: *query.CursorPage[model.User] - 当前页及前后页游标, error - 条件或游标不合法时为 query.ErrInvalidSpec
+func (r *UserRepository) ListCursor(ctx context.Context, req query.CursorRequest, spec query.Spec) (*query.CursorPage[model.User], error) {
+	return query.FindPage[model.User](r.db.WithContext(ctx), UserListSchema, spec, req)
+}
+
+// Count 统计符合条件的用户数量
+// 参数: ctx - 请求上下文, spec - 过滤条件（排序被忽略）
+// 返回: int64 - 用户数量, error - 条件不合法时为 query.ErrInvalidSpec
+func (r *UserRepository) Count(ctx context.Context, spec query.Spec) (int64, error) {
+	db, err := UserListSchema.Filter(r.db.WithContext(ctx).Model(&model.User{}), spec)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	err = db.Count(&total).Error
+	return total, err
+}
+
+// Stream 按条件逐行读取用户并调用 fn，不会一次性加载全部结果，用于导出
+// 参数: ctx - 请求上下文, spec - 过滤和排序条件, fn - 处理单个用户，返回错误时停止读取
+// 返回: error - 查询失败、条件不合法或 fn 返回的错误
+func (r *UserRepository) Stream(ctx context.Context, spec query.Spec, fn func(*model.User) error) error {
+	db, err := UserListSchema.Apply(r.db.WithContext(ctx).Model(&model.User{}), spec)
+	if err != nil {
+		return err
+	}
+	rows, err := db.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user model.User
+		if err := r.db.ScanRows(rows, &user); err != nil {
+			return err
+		}
+		if err := fn(&user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // CheckUsernameExists 检查用户名是否已存在
-// 参数: username - 用户名
+// 参数: ctx - 请求上下文, username - 用户名
 // 返回: bool - 是否存在, error - 查询是否成功
-func (r *UserRepository) CheckUsernameExists(username string) (bool, error) {
+func (r *UserRepository) CheckUsernameExists(ctx context.Context, username string) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.User{}).Where("username = ?", username).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("username = ?", username).Count(&count).Error
 	return count > 0, err
 }
 
 // CheckEmailExists 检查邮箱是否已存在
-// 参数: email - 邮箱地址
+// 参数: ctx - 请求上下文, email - 邮箱地址
 // 返回: bool - 是否存在, error - 查询是否成功
-func (r *UserRepository) CheckEmailExists(email string) (bool, error) {
+func (r *UserRepository) CheckEmailExists(ctx context.Context, email string) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.User{}).Where("email = ?", email).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("email = ?", email).Count(&count).Error
 	return count > 0, err
 }
 
 // CheckUsernameExistsExcludeID 检查用户名是否已存在（排除指定ID）
-// 参数: username - 用户名, excludeID - 排除的用户ID（用于更新时排除自己）
+// 参数: ctx - 请求上下文, username - 用户名, excludeID - 排除的用户ID（用于更新时排除自己）
 // 返回: bool - 是否存在, error - 查询是否成功
-func (r *UserRepository) CheckUsernameExistsExcludeID(username string, excludeID uint) (bool, error) {
+func (r *UserRepository) CheckUsernameExistsExcludeID(ctx context.Context, username string, excludeID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.User{}).Where("username = ? AND id != ?", username, excludeID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("username = ? AND id != ?", username, excludeID).Count(&count).Error
 	return count > 0, err
 }
 
 // CheckEmailExistsExcludeID 检查邮箱是否已存在（排除指定ID）
-// 参数: email - 邮箱地址, excludeID - 排除的用户ID（用于更新时排除自己）
+// 参数: ctx - 请求上下文, email - 邮箱地址, excludeID - 排除的用户ID（用于更新时排除自己）
 // 返回: bool - 是否存在, error - 查询是否成功
-func (r *UserRepository) CheckEmailExistsExcludeID(email string, excludeID uint) (bool, error) {
+func (r *UserRepository) CheckEmailExistsExcludeID(ctx context.Context, email string, excludeID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.User{}).Where("email = ? AND id != ?", email, excludeID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("email = ? AND id != ?", email, excludeID).Count(&count).Error
 	return count > 0, err
 }
+
+// FindExistingUsernames 批量检查用户名，一次查询返回其中已存在的用户名
+// 参数: ctx - 请求上下文, usernames - 待检查的用户名
+// 返回: []string - 已存在的用户名, error - 查询是否成功
+func (r *UserRepository) FindExistingUsernames(ctx context.Context, usernames []string) ([]string, error) {
+	return r.findExisting(ctx, "username", usernames)
+}
+
+// FindExistingEmails 批量检查邮箱（不区分大小写），一次查询返回其中已存在的邮箱
+// 参数: ctx - 请求上下文, emails - 待检查的邮箱地址
+// 返回: []string - 已存在的邮箱（小写形式）, error - 查询是否成功
+func (r *UserRepository) FindExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	lower := make([]string, len(emails))
+	for i, email := range emails {
+		lower[i] = strings.ToLower(email)
+	}
+	return r.findExisting(ctx, "LOWER(email)", lower)
+}
+
+// findExisting 使用 IN 查询返回 column（列名或表达式）取值在 values 中的记录值
+func (r *UserRepository) findExisting(ctx context.Context, column string, values []string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	var existing []string
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where(column+" IN ?", values).Pluck(column, &existing).Error
+	return existing, err
+}
@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/query"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+const (
+	userIDKeyPrefix   = "user:id:"
+	userNameKeyPrefix = "user:name:"
+
+	// negativeCacheValue 标记用户不存在的缓存值
+	negativeCacheValue = "-"
+
+	defaultUserCacheTTL    = 10 * time.Minute
+	defaultUserNegativeTTL = 30 * time.Second
+
+	// cacheOpTimeout 单次缓存操作超时，避免缓存故障拖慢数据库查询
+	cacheOpTimeout = 500 * time.Millisecond
+)
+
+// UserStore 用户数据访问接口（方法与 service.UserRepositoryInterface 一致）
+type UserStore interface {
+	Create(ctx context.Context, user *model.User) error
+	CreateBatch(ctx context.Context, users []*model.User, batchSize int) error
+	GetByID(ctx context.Context, id uint) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetCredentials(ctx context.Context, username string) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	Update(ctx context.Context, user *model.User) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int, spec query.Spec) ([]model.User, int64, error)
+	ListCursor(ctx context.Context, req query.CursorRequest, spec query.Spec) (*query.CursorPage[model.User], error)
+	Count(ctx context.Context, spec query.Spec) (int64, error)
+	Stream(ctx context.Context, spec query.Spec, fn func(*model.User) error) error
+	CheckUsernameExists(ctx context.Context, username string) (bool, error)
+	CheckEmailExists(ctx context.Context, email string) (bool, error)
+	CheckUsernameExistsExcludeID(ctx context.Context, username string, excludeID uint) (bool, error)
+	CheckEmailExistsExcludeID(ctx context.Context, email string, excludeID uint) (bool, error)
+	FindExistingUsernames(ctx context.Context, usernames []string) ([]string, error)
+	FindExistingEmails(ctx context.Context, emails []string) ([]string, error)
+}
+
+// 确保 UserRepository 和 CachedUserRepository 实现了 UserStore 接口
+var (
+	_ UserStore = (*UserRepository)(nil)
+	_ UserStore = (*CachedUserRepository)(nil)
+)
+
+// cacheLog 用户缓存日志器
+var cacheLog = logger.Component("repository.user_cache")
+
+// CachedUserRepository 带读缓存的用户仓库
+// 按 ID 缓存用户信息，用户名到 ID 的映射单独缓存；Update/Delete 时失效。
+// 同一个键的并发未命中通过 singleflight 合并为一次数据库查询，
+// 不存在的 ID 以短 TTL 缓存，防止缓存穿透。
+// 缓存读写失败时直接回源数据库，不影响业务。
+type CachedUserRepository struct {
+	repo        UserStore
+	store       cache.Store
+	ttl         time.Duration
+	negativeTTL time.Duration
+	group       singleflight.Group
+}
+
+// cachedUser 用户缓存结构
+// 不缓存密码哈希：缓存可能是多个环境共享的 Redis，需要校验密码时使用 GetCredentials 查询数据库
+type cachedUser struct {
+	ID        uint      `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewCachedUserRepository 创建带读缓存的用户仓库
+// ttl/negativeTTL <= 0 时分别使用 10 分钟和 30 秒
+func NewCachedUserRepository(repo UserStore, store cache.Store, ttl, negativeTTL time.Duration) *CachedUserRepository {
+	if ttl <= 0 {
+		ttl = defaultUserCacheTTL
+	}
+	if negativeTTL <= 0 {
+		negativeTTL = defaultUserNegativeTTL
+	}
+	return &CachedUserRepository{
+		repo:        repo,
+		store:       store,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+	}
+}
+
+func userIDKey(id uint) string {
+	return userIDKeyPrefix + strconv.FormatUint(uint64(id), 10)
+}
+
+func userNameKey(username string) string {
+	return userNameKeyPrefix + username
+}
+
+// cacheContext 创建缓存操作的上下文，在请求上下文的基础上限制超时
+func cacheContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, cacheOpTimeout)
+}
+
+// Create 新增用户，并清除该 ID 可能残留的不存在标记
+func (r *CachedUserRepository) Create(ctx context.Context, user *model.User) error {
+	if err := r.repo.Create(ctx, user); err != nil {
+		return err
+	}
+	r.invalidate(ctx, user.ID, user.Username)
+	return nil
+}
+
+// CreateBatch 批量新增用户，并清除这些 ID 可能残留的不存在标记
+func (r *CachedUserRepository) CreateBatch(ctx context.Context, users []*model.User, batchSize int) error {
+	if err := r.repo.CreateBatch(ctx, users, batchSize); err != nil {
+		return err
+	}
+	for _, user := range users {
+		r.invalidate(ctx, user.ID, user.Username)
+	}
+	return nil
+}
+
+// GetByID 根据 ID 获取用户，优先读取缓存，返回的用户不包含密码哈希
+func (r *CachedUserRepository) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	if user, found, hit := r.getCachedByID(ctx, id); hit {
+		if !found {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return user, nil
+	}
+
+	v, err, _ := r.group.Do(userIDKey(id), func() (interface{}, error) {
+		// 合并后的查询由多个调用方共享，不随单个调用方取消而中断；
+		// 回填缓存的数据从主库读取，避免缓存副本上的旧数据直到 TTL 过期
+		ctx := database.WithPrimary(context.WithoutCancel(ctx))
+		user, err := r.repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				r.setNegative(ctx, id)
+			}
+			return nil, err
+		}
+		r.setUser(ctx, user)
+		return user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyUser(v.(*model.User)), nil
+}
+
+// GetByUsername 根据用户名获取用户，通过用户名到 ID 的映射复用 ID 缓存
+func (r *CachedUserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	if id, ok := r.getCachedID(ctx, username); ok {
+		if user, found, hit := r.getCachedByID(ctx, id); hit && found && user.Username == username {
+			return user, nil
+		}
+	}
+
+	v, err, _ := r.group.Do(userNameKey(username), func() (interface{}, error) {
+		// 合并后的查询由多个调用方共享，不随单个调用方取消而中断；回填缓存的数据从主库读取
+		ctx := database.WithPrimary(context.WithoutCancel(ctx))
+		user, err := r.repo.GetByUsername(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		r.setUser(ctx, user)
+		return user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyUser(v.(*model.User)), nil
+}
+
+// GetCredentials 获取用户及密码哈希，直接查询数据库
+func (r *CachedUserRepository) GetCredentials(ctx context.Context, username string) (*model.User, error) {
+	return r.repo.GetCredentials(ctx, username)
+}
+
+func (r *CachedUserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.repo.GetByEmail(ctx, email)
+}
+
+// Update 更新用户信息，并使新旧用户名和 ID 缓存失效
+func (r *CachedUserRepository) Update(ctx context.Context, user *model.User) error {
+	oldUsername := r.cachedUsername(ctx, user.ID)
+	if err := r.repo.Update(ctx, user); err != nil {
+		return err
+	}
+	r.invalidate(ctx, user.ID, user.Username, oldUsername)
+	return nil
+}
+
+// Delete 删除用户，并使相关缓存失效
+func (r *CachedUserRepository) Delete(ctx context.Context, id uint) error {
+	oldUsername := r.cachedUsername(ctx, id)
+	if err := r.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id, oldUsername)
+	return nil
+}
+
+func (r *CachedUserRepository) List(ctx context.Context, offset, limit int, spec query.Spec) ([]model.User, int64, error) {
+	return r.repo.List(ctx, offset, limit, spec)
+}
+
+func (r *CachedUserRepository) ListCursor(ctx context.Context, req query.CursorRequest, spec query.Spec) (*query.CursorPage[model.User], error) {
+	return r.repo.ListCursor(ctx, req, spec)
+}
+
+func (r *CachedUserRepository) Count(ctx context.Context, spec query.Spec) (int64, error) {
+	return r.repo.Count(ctx, spec)
+}
+
+func (r *CachedUserRepository) Stream(ctx context.Context, spec query.Spec, fn func(*model.User) error) error {
+	return r.repo.Stream(ctx, spec, fn)
+}
+
+func (r *CachedUserRepository) CheckUsernameExists(ctx context.Context, username string) (bool, error) {
+	return r.repo.CheckUsernameExists(ctx, username)
+}
+
+func (r *CachedUserRepository) CheckEmailExists(ctx context.Context, email string) (bool, error) {
+	return r.repo.CheckEmailExists(ctx, email)
+}
+
+func (r *CachedUserRepository) CheckUsernameExistsExcludeID(ctx context.Context, username string, excludeID uint) (bool, error) {
+	return r.repo.CheckUsernameExistsExcludeID(ctx, username, excludeID)
+}
+
+func (r *CachedUserRepository) CheckEmailExistsExcludeID(ctx context.Context, email string, excludeID uint) (bool, error) {
+	return r.repo.CheckEmailExistsExcludeID(ctx, email, excludeID)
+}
+
+func (r *CachedUserRepository) FindExistingUsernames(ctx context.Context, usernames []string) ([]string, error) {
+	return r.repo.FindExistingUsernames(ctx, usernames)
+}
+
+func (r *CachedUserRepository) FindExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	return r.repo.FindExistingEmails(ctx, emails)
+}
+
+// getCachedByID 读取 ID 缓存
+// 返回: user - 用户对象, found - 用户是否存在, hit - 是否命中缓存
+func (r *CachedUserRepository) getCachedByID(ctx context.Context, id uint) (user *model.User, found bool, hit bool) {
+	ctx, cancel := cacheContext(ctx)
+	defer cancel()
+
+	val, err := r.store.Get(ctx, userIDKey(id))
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			cacheLog.Warn("读取用户缓存失败", zap.Uint("user_id", id), zap.Error(err))
+		}
+		return nil, false, false
+	}
+	if val == negativeCacheValue {
+		return nil, false, true
+	}
+
+	var cu cachedUser
+	if err := json.Unmarshal([]byte(val), &cu); err != nil {
+		cacheLog.Warn("解析用户缓存失败", zap.Uint("user_id", id), zap.Error(err))
+		return nil, false, false
+	}
+	return cu.toModel(), true, true
+}
+
+// getCachedID 读取用户名到 ID 的映射
+func (r *CachedUserRepository) getCachedID(ctx context.Context, username string) (uint, bool) {
+	ctx, cancel := cacheContext(ctx)
+	defer cancel()
+
+	val, err := r.store.Get(ctx, userNameKey(username))
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			cacheLog.Warn("读取用户名缓存失败", zap.String("username", username), zap.Error(err))
+		}
+		return 0, false
+	}
+	id, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// cachedUsername 获取缓存中该用户的用户名，用于失效旧的用户名映射
+func (r *CachedUserRepository) cachedUsername(ctx context.Context, id uint) string {
+	user, found, hit := r.getCachedByID(ctx, id)
+	if !hit || !found {
+		return ""
+	}
+	return user.Username
+}
+
+// setUser 写入用户缓存和用户名映射
+func (r *CachedUserRepository) setUser(ctx context.Context, user *model.User) {
+	data, err := json.Marshal(newCachedUser(user))
+	if err != nil {
+		cacheLog.Warn("序列化用户缓存失败", zap.Uint("user_id", user.ID), zap.Error(err))
+		return
+	}
+
+	ctx, cancel := cacheContext(ctx)
+	defer cancel()
+
+	if err := r.store.Set(ctx, userIDKey(user.ID), data, r.ttl); err != nil {
+		cacheLog.Warn("写入用户缓存失败", zap.Uint("user_id", user.ID), zap.Error(err))
+		return
+	}
+	if err := r.store.Set(ctx, userNameKey(user.Username), user.ID, r.ttl); err != nil {
+		cacheLog.Warn("写入用户名缓存失败", zap.String("username", user.Username), zap.Error(err))
+	}
+}
+
+// setNegative 缓存不存在的用户ID
+func (r *CachedUserRepository) setNegative(ctx context.Context, id uint) {
+	ctx, cancel := cacheContext(ctx)
+	defer cancel()
+
+	if err := r.store.Set(ctx, userIDKey(id), negativeCacheValue, r.negativeTTL); err != nil {
+		cacheLog.Warn("写入用户不存在标记失败", zap.Uint("user_id", id), zap.Error(err))
+	}
+}
+
+// invalidate 删除用户 ID 缓存及用户名映射
+func (r *CachedUserRepository) invalidate(ctx context.Context, id uint, usernames ...string) {
+	keys := []string{userIDKey(id)}
+	for _, username := range usernames {
+		if username != "" {
+			keys = append(keys, userNameKey(username))
+		}
+	}
+
+	// 数据已写入数据库，失效操作不随请求取消而中断
+	ctx, cancel := cacheContext(context.WithoutCancel(ctx))
+	defer cancel()
+
+	if err := r.store.Del(ctx, keys...); err != nil {
+		// 失效失败时缓存会在 TTL 到期后自然过期
+		cacheLog.Error("删除用户缓存失败",
+			zap.Uint("user_id", id),
+			zap.Strings("keys", keys),
+			zap.Error(err))
+	}
+}
+
+func newCachedUser(user *model.User) cachedUser {
+	return cachedUser{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		Status:    user.Status,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func (cu cachedUser) toModel() *model.User {
+	return &model.User{
+		ID:        cu.ID,
+		Username:  cu.Username,
+		Email:     cu.Email,
+		Role:      cu.Role,
+		Status:    cu.Status,
+		CreatedAt: cu.CreatedAt,
+		UpdatedAt: cu.UpdatedAt,
+	}
+}
+
+// copyUser 返回不含密码哈希的副本，避免 singleflight 共享结果被调用方修改，并与从缓存读取的结果一致
+func copyUser(user *model.User) *model.User {
+	u := *user
+	u.Password = ""
+	return &u
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeUserStore 记录调用次数的内存用户仓库
+type fakeUserStore struct {
+	UserStore
+
+	mu     sync.Mutex
+	users  map[uint]model.User
+	nextID uint
+	delay  time.Duration
+
+	getByIDCalls       int32
+	getByUsernameCalls int32
+}
+
+func newFakeUserStore() *fakeUserStore {
+	return &fakeUserStore{users: make(map[uint]model.User), nextID: 1}
+}
+
+func (f *fakeUserStore) Create(ctx context.Context, user *model.User) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user.ID = f.nextID
+	f.nextID++
+	f.users[user.ID] = *user
+	return nil
+}
+
+func (f *fakeUserStore) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	atomic.AddInt32(&f.getByIDCalls, 1)
+	time.Sleep(f.delay)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user, ok := f.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+func (f *fakeUserStore) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	atomic.AddInt32(&f.getByUsernameCalls, 1)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, user := range f.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserStore) GetCredentials(ctx context.Context, username string) (*model.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, user := range f.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserStore) Update(ctx context.Context, user *model.User) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[user.ID] = *user
+	return nil
+}
+
+func (f *fakeUserStore) Delete(ctx context.Context, id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.users, id)
+	return nil
+}
+
+func setupCachedUserRepository(t *testing.T) (*CachedUserRepository, *fakeUserStore) {
+	t.Helper()
+	if logger.Logger == nil {
+		logger.Init("error")
+	}
+
+	store := cache.NewMemoryStore(time.Minute)
+	t.Cleanup(func() { store.Close() })
+
+	inner := newFakeUserStore()
+	return NewCachedUserRepository(inner, store, time.Minute, time.Minute), inner
+}
+
+func TestCachedUserRepository_GetByIDCachesResult(t *testing.T) {
+	repo, inner := setupCachedUserRepository(t)
+	ctx := context.Background()
+
+	user := &model.User{Username: "alice", Email: "alice@example.com", Password: "hashed"}
+	require.NoError(t, repo.Create(ctx, user))
+
+	for i := 0; i < 3; i++ {
+		got, err := repo.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", got.Username)
+		// 密码哈希不进入缓存，也不从缓存仓库返回
+		assert.Empty(t, got.Password)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&inner.getByIDCalls))
+
+	data, err := repo.store.Get(ctx, userIDKey(user.ID))
+	require.NoError(t, err)
+	assert.NotContains(t, data, "hashed")
+
+	// 登录校验直接查询数据库
+	creds, err := repo.GetCredentials(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "hashed", creds.Password)
+
+	// 按用户名查询复用 ID 缓存
+	got, err := repo.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, got.ID)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&inner.getByUsernameCalls))
+}
+
+func TestCachedUserRepository_InvalidateOnUpdateAndDelete(t *testing.T) {
+	repo, inner := setupCachedUserRepository(t)
+	ctx := context.Background()
+
+	user := &model.User{Username: "bob", Email: "bob@example.com"}
+	require.NoError(t, repo.Create(ctx, user))
+	_, err := repo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+
+	user.Username = "robert"
+	require.NoError(t, repo.Update(ctx, user))
+
+	got, err := repo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "robert", got.Username)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&inner.getByIDCalls))
+
+	_, err = repo.GetByUsername(ctx, "bob")
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	require.NoError(t, repo.Delete(ctx, user.ID))
+	_, err = repo.GetByID(ctx, user.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestCachedUserRepository_NegativeCache(t *testing.T) {
+	repo, inner := setupCachedUserRepository(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := repo.GetByID(ctx, 1)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&inner.getByIDCalls))
+
+	// 新建用户后清除不存在标记
+	user := &model.User{Username: "carol", Email: "carol@example.com"}
+	require.NoError(t, repo.Create(ctx, user))
+	require.Equal(t, uint(1), user.ID)
+
+	got, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "carol", got.Username)
+}
+
+func TestCachedUserRepository_SingleflightCollapsesMisses(t *testing.T) {
+	repo, inner := setupCachedUserRepository(t)
+	ctx := context.Background()
+
+	user := &model.User{Username: "dave", Email: "dave@example.com"}
+	require.NoError(t, repo.Create(ctx, user))
+	inner.delay = 50 * time.Millisecond
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := repo.GetByID(ctx, user.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, "dave", got.Username)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&inner.getByIDCalls))
+}
+
+func TestCachedUserRepository_RefillReadsPrimary(t *testing.T) {
+	if logger.Logger == nil {
+		logger.Init("error")
+	}
+	ctx := context.Background()
+
+	// 主库和副本是两个 SQLite 文件，副本不会同步主库的写入，相当于一直延迟的副本
+	dir := t.TempDir()
+	newDB := func(name string) *gorm.DB {
+		db, err := database.Init(config.Database{
+			Driver: config.DatabaseDriverSQLite,
+			Name:   filepath.Join(dir, name),
+			Log:    config.DatabaseLogConfig{Level: config.DatabaseLogSilent},
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.AutoMigrate(&model.User{}))
+		require.NoError(t, db.Create(&model.User{Username: "alice", Email: "alice@example.com", Password: "x", Role: "user"}).Error)
+		return db
+	}
+	replica := newDB("replica.db")
+	sqlDB, _ := replica.DB()
+	sqlDB.Close()
+	db := newDB("primary.db")
+
+	cfg := config.Database{
+		Driver:   config.DatabaseDriverSQLite,
+		Name:     filepath.Join(dir, "primary.db"),
+		Replicas: []config.DatabaseReplica{{Name: filepath.Join(dir, "replica.db")}},
+	}
+	rs, err := database.UseReplicas(db, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		rs.Close(context.Background())
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	store := cache.NewMemoryStore(time.Minute)
+	t.Cleanup(func() { store.Close() })
+	repo := NewCachedUserRepository(NewUserRepository(db), store, time.Minute, time.Minute)
+
+	user, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	user.Role = "admin"
+	require.NoError(t, repo.Update(ctx, user))
+
+	// 更新后缓存失效，回填时从主库读取，不会把副本上的旧数据缓存下来
+	for i := 0; i < 2; i++ {
+		got, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "admin", got.Role)
+	}
+	got, err := repo.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", got.Role)
+
+	// 普通读操作仍然使用副本
+	var role string
+	require.NoError(t, db.WithContext(ctx).Model(&model.User{}).Where("id = ?", 1).Pluck("role", &role).Error)
+	assert.Equal(t, "user", role)
+}
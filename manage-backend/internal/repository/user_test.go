@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestDB 创建 SQLite 内存数据库，不依赖外部数据库服务
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.Init(config.Database{
+		Driver: config.DatabaseDriverSQLite,
+		Name:   ":memory:",
+		Log:    config.DatabaseLogConfig{Level: config.DatabaseLogSilent},
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.User{}))
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestUserRepositoryListAndExists(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(newTestDB(t))
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		require.NoError(t, repo.Create(ctx, &model.User{Username: name, Email: name + "@example.com", Password: "x"}))
+	}
+	require.NoError(t, repo.Delete(ctx, 3))
+
+	users, total, err := repo.List(ctx, 0, 1, query.Spec{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, users, 1)
+
+	exists, err := repo.CheckUsernameExists(ctx, "bob")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.CheckUsernameExistsExcludeID(ctx, "bob", 2)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = repo.GetByID(ctx, 3)
+	assert.Error(t, err)
+}
+
+func TestUserRepositoryListFilterAndSort(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, u := range []model.User{
+		{Username: "Alice", Email: "alice@example.com", Role: "admin", Status: "active"},
+		{Username: "bob", Email: "bob@test.org", Role: "user", Status: "active"},
+		{Username: "carol_1", Email: "carol@example.com", Role: "user", Status: "inactive"},
+		{Username: "carolX1", Email: "carolx@example.com", Role: "user", Status: "active"},
+	} {
+		u.Password = "x"
+		u.CreatedAt = base.AddDate(0, 0, i)
+		require.NoError(t, repo.Create(ctx, &u))
+	}
+
+	names := func(spec query.Spec) []string {
+		t.Helper()
+		users, _, err := repo.List(ctx, 0, 10, spec)
+		require.NoError(t, err)
+		var out []string
+		for _, u := range users {
+			out = append(out, u.Username)
+		}
+		return out
+	}
+
+	var spec query.Spec
+	spec.Where("username", query.OpContains, "ALI")
+	assert.Equal(t, []string{"Alice"}, names(spec))
+
+	// 通配符按字面匹配
+	spec = query.Spec{}
+	spec.Where("username", query.OpContains, "_1")
+	assert.Equal(t, []string{"carol_1"}, names(spec))
+
+	spec = query.Spec{Sorts: []query.Sort{{Field: "status", Desc: true}, {Field: "username", Desc: true}}}
+	spec.Where("role", query.OpIn, []string{"user"}).Where("created_at", query.OpGte, base.AddDate(0, 0, 1))
+	assert.Equal(t, []string{"carol_1", "carolX1", "bob"}, names(spec))
+
+	users, total, err := repo.List(ctx, 0, 1, spec)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, users, 1)
+
+	for _, bad := range []query.Spec{
+		{Sorts: []query.Sort{{Field: "password"}}},
+		{Sorts: []query.Sort{{Field: "id; DROP TABLE users"}}},
+		{Filters: []query.Filter{{Field: "password", Op: query.OpEq, Value: "x"}}},
+		{Filters: []query.Filter{{Field: "role", Op: query.OpContains, Value: "adm"}}},
+	} {
+		_, _, err := repo.List(ctx, 0, 10, bad)
+		assert.ErrorIs(t, err, query.ErrInvalidSpec)
+	}
+}
+
+func TestUserRepositoryCountAndStream(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(newTestDB(t))
+
+	for _, name := range []string{"alice", "bob", "carol", "dave"} {
+		require.NoError(t, repo.Create(ctx, &model.User{Username: name, Email: name + "@example.com", Password: "x"}))
+	}
+	require.NoError(t, repo.Delete(ctx, 4))
+
+	spec := query.Spec{Sorts: []query.Sort{{Field: "username", Desc: true}}}
+	total, err := repo.Count(ctx, spec)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	var names []string
+	err = repo.Stream(ctx, spec, func(u *model.User) error {
+		names = append(names, u.Username)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carol", "bob", "alice"}, names)
+
+	// fn 返回错误时停止读取
+	stop := errors.New("stop")
+	var n int
+	err = repo.Stream(ctx, spec, func(u *model.User) error {
+		n++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, n)
+
+	_, err = repo.Count(ctx, *(&query.Spec{}).Where("password", query.OpEq, "x"))
+	assert.ErrorIs(t, err, query.ErrInvalidSpec)
+}
+
+func TestUserRepositoryCreateBatch(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(newTestDB(t))
+
+	users := []*model.User{
+		{Username: "alice", Email: "alice@example.com", Password: "x"},
+		{Username: "bob", Email: "bob@example.com", Password: "x"},
+		{Username: "carol", Email: "carol@example.com", Password: "x"},
+	}
+	require.NoError(t, repo.CreateBatch(ctx, users, 2))
+	for _, u := range users {
+		assert.NotZero(t, u.ID)
+	}
+
+	// 任一用户冲突时整个事务回滚
+	err := repo.CreateBatch(ctx, []*model.User{
+		{Username: "dave", Email: "dave@example.com", Password: "x"},
+		{Username: "alice", Email: "alice2@example.com", Password: "x"},
+	}, 1)
+	assert.Error(t, err)
+	exists, err := repo.CheckUsernameExists(ctx, "dave")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestUserRepositoryFindExisting(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(newTestDB(t))
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		require.NoError(t, repo.Create(ctx, &model.User{Username: name, Email: name + "@example.com", Password: "x"}))
+	}
+	require.NoError(t, repo.Delete(ctx, 3))
+
+	usernames, err := repo.FindExistingUsernames(ctx, []string{"alice", "carol", "dave", "bob"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"alice", "bob"}, usernames, "soft-deleted users are ignored")
+
+	emails, err := repo.FindExistingEmails(ctx, []string{"Bob@Example.com", "eve@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob@example.com"}, emails, "emails are compared case-insensitively")
+
+	emails, err = repo.FindExistingEmails(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, emails)
+}
+
+func TestUserRepositoryUpdateWritesOnlyUpdatableColumns(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(newTestDB(t))
+
+	user := &model.User{Username: "alice", Email: "alice@example.com", Password: "hashed"}
+	require.NoError(t, repo.Create(ctx, user))
+
+	// 无论密码字段为空（来自缓存）还是其他值，Update 都不修改密码
+	for _, password := range []string{"", "other"} {
+		update := *user
+		update.Password = password
+		update.Role = "admin"
+		update.Status = "inactive"
+		require.NoError(t, repo.Update(ctx, &update))
+
+		got, err := repo.GetCredentials(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "hashed", got.Password)
+		assert.Equal(t, "admin", got.Role)
+		assert.Equal(t, "inactive", got.Status)
+		assert.False(t, got.UpdatedAt.Before(user.UpdatedAt))
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// blacklistEntry 本地黑名单缓存条目
+type blacklistEntry struct {
+	blacklisted bool
+	expiresAt   time.Time
+}
+
+// localBlacklistCache 进程内的短期黑名单缓存
+// Redis 短暂不可用时，用最近一次确认过的结果兜底判断 Token 是否被吊销
+type localBlacklistCache struct {
+	mu      sync.RWMutex
+	entries map[string]blacklistEntry
+	maxSize int
+}
+
+// newLocalBlacklistCache 创建本地黑名单缓存
+func newLocalBlacklistCache(maxSize int) *localBlacklistCache {
+	return &localBlacklistCache{
+		entries: make(map[string]blacklistEntry),
+		maxSize: maxSize,
+	}
+}
+
+// Get 获取缓存的黑名单结果，ok 为 false 表示未缓存或已过期
+func (c *localBlacklistCache) Get(jti string) (blacklisted bool, ok bool) {
+	c.mu.RLock()
+	entry, exists := c.entries[jti]
+	c.mu.RUnlock()
+
+	if !exists || time.Now().After(entry.expiresAt) {
+		return false, false
+	}
+	return entry.blacklisted, true
+}
+
+// Set 缓存黑名单结果
+func (c *localBlacklistCache) Set(jti string, blacklisted bool, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.maxSize {
+		c.evictLocked()
+	}
+	// 清理后仍然已满时，只保留已吊销的结果
+	if len(c.entries) >= c.maxSize && !blacklisted {
+		return
+	}
+
+	c.entries[jti] = blacklistEntry{
+		blacklisted: blacklisted,
+		expiresAt:   time.Now().Add(ttl),
+	}
+}
+
+// evictLocked 清理过期条目，仍然超限时丢弃未吊销的条目（调用方需持有写锁）
+func (c *localBlacklistCache) evictLocked() {
+	now := time.Now()
+	for jti, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, jti)
+		}
+	}
+
+	if len(c.entries) < c.maxSize {
+		return
+	}
+	for jti, entry := range c.entries {
+		if !entry.blacklisted {
+			delete(c.entries, jti)
+		}
+	}
+}
@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
 	"github.com/mojocn/base64Captcha"
-	"github.com/redis/go-redis/v9"
 )
 
 // CaptchaConfig 验证码配置
@@ -25,7 +26,6 @@ type CaptchaConfig struct {
 type CaptchaService struct {
 	store  base64Captcha.Store
 	driver base64Captcha.Driver
-	rdb    *redis.Client
 }
 
 // CaptchaResponse 验证码响应
@@ -35,7 +35,7 @@ type CaptchaResponse struct {
 }
 
 // NewCaptchaService 创建验证码服务实例
-func NewCaptchaService(rdb *redis.Client, config CaptchaConfig) *CaptchaService {
+func NewCaptchaService(cacheStore cache.Store, config CaptchaConfig) *CaptchaService {
 	var driver base64Captcha.Driver
 	
 	// 根据配置创建不同类型的验证码驱动
@@ -66,13 +66,12 @@ func NewCaptchaService(rdb *redis.Client, config CaptchaConfig) *CaptchaService
 		)
 	}
 	
-	// 使用 Redis 作为存储
-	store := NewRedisCaptchaStore(rdb, config.Expiration)
+	// 使用缓存存储（Redis 或内存）保存验证码答案
+	store := NewRedisCaptchaStore(cacheStore, config.Expiration)
 	
 	return &CaptchaService{
 		store:  store,
 		driver: driver,
-		rdb:    rdb,
 	}
 }
 
@@ -81,6 +80,7 @@ func (s *CaptchaService) GenerateCaptcha() (*CaptchaResponse, error) {
 	captcha := base64Captcha.NewCaptcha(s.driver, s.store)
 	
 	id, b64s, answer, err := captcha.Generate()
+	metrics.RecordCaptchaGenerated(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate captcha: %w", err)
 	}
@@ -98,22 +98,26 @@ func (s *CaptchaService) GenerateCaptcha() (*CaptchaResponse, error) {
 // VerifyCaptcha 验证验证码
 func (s *CaptchaService) VerifyCaptcha(captchaID, captchaValue string) bool {
 	if captchaID == "" || captchaValue == "" {
+		metrics.RecordCaptchaVerified(false)
 		return false
 	}
 	
-	return s.store.Verify(captchaID, captchaValue, true) // true 表示验证后清除
+	ok := s.store.Verify(captchaID, captchaValue, true) // true 表示验证后清除
+	metrics.RecordCaptchaVerified(ok)
+	return ok
 }
 
-// RedisCaptchaStore Redis 验证码存储实现
+// RedisCaptchaStore 验证码存储实现
+// 基于 cache.Store，默认使用 Redis，单节点部署或测试时可使用内存存储
 type RedisCaptchaStore struct {
-	rdb        *redis.Client
+	store      cache.Store
 	expiration time.Duration
 }
 
-// NewRedisCaptchaStore 创建 Redis 验证码存储
-func NewRedisCaptchaStore(rdb *redis.Client, expiration time.Duration) *RedisCaptchaStore {
+// NewRedisCaptchaStore 创建验证码存储
+func NewRedisCaptchaStore(store cache.Store, expiration time.Duration) *RedisCaptchaStore {
 	return &RedisCaptchaStore{
-		rdb:        rdb,
+		store:      store,
 		expiration: expiration,
 	}
 }
@@ -121,7 +125,7 @@ func NewRedisCaptchaStore(rdb *redis.Client, expiration time.Duration) *RedisCap
 // Set 存储验证码
 func (r *RedisCaptchaStore) Set(id string, value string) error {
 	key := r.getCaptchaKey(id)
-	return r.rdb.Set(context.Background(), key, value, r.expiration).Err()
+	return r.store.Set(context.Background(), key, value, r.expiration)
 }
 
 // Get 获取验证码
@@ -129,13 +133,13 @@ func (r *RedisCaptchaStore) Get(id string, clear bool) string {
 	key := r.getCaptchaKey(id)
 	ctx := context.Background()
 	
-	val, err := r.rdb.Get(ctx, key).Result()
+	val, err := r.store.Get(ctx, key)
 	if err != nil {
 		return ""
 	}
 	
 	if clear {
-		r.rdb.Del(ctx, key)
+		r.store.Del(ctx, key)
 	}
 	
 	return val
@@ -147,7 +151,7 @@ func (r *RedisCaptchaStore) Verify(id, answer string, clear bool) bool {
 	return storedAnswer != "" && storedAnswer == answer
 }
 
-// getCaptchaKey 获取验证码在 Redis 中的键
+// getCaptchaKey 获取验证码在缓存中的键
 func (r *RedisCaptchaStore) getCaptchaKey(id string) string {
 	return fmt.Sprintf("captcha:%s", id)
 }
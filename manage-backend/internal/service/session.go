@@ -3,13 +3,30 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
 )
 
+// ErrSessionStoreUnavailable 会话存储不可用（fail-closed 模式下无法确认 Token 状态）
+var ErrSessionStoreUnavailable = errors.New("session store unavailable")
+
+// sessionLog 会话服务日志器
+var sessionLog = logger.Component("service.session")
+
+// activeSessionsKey 活跃会话集合，成员为拥有会话的用户ID
+const activeSessionsKey = "user:sessions"
+
+// sessionCleanupBatchSize 清理过期会话时每批检查的会话数
+const sessionCleanupBatchSize = 500
+
 // SessionInfo 表示用户会话信息
 // - UserID: 用户ID
 // - Username: 用户名
@@ -32,20 +49,80 @@ type SessionInfo struct {
 
 // SessionService 会话服务
 // 负责管理用户会话、令牌黑名单、用户活跃状态以及权限缓存
+// 所有缓存访问都经过熔断器，缓存存储故障时按 FailureMode 决定放行还是拒绝
 type SessionService struct {
-	redisClient *cache.RedisClient
-	jwtManager  *auth.JWTManager
+	store          cache.Store
+	jwtManager     *auth.JWTManager
+	config         config.SessionConfig
+	breaker        *cache.CircuitBreaker
+	localBlacklist *localBlacklistCache
 }
 
 // NewSessionService 创建会话服务实例
-func NewSessionService(redisClient *cache.RedisClient, jwtManager *auth.JWTManager) *SessionService {
+// cfg 中未设置的字段使用默认值
+func NewSessionService(store cache.Store, jwtManager *auth.JWTManager, cfg config.SessionConfig) *SessionService {
+	cfg = cfg.WithDefaults()
+
 	return &SessionService{
-		redisClient: redisClient,
-		jwtManager:  jwtManager,
+		store:          store,
+		jwtManager:     jwtManager,
+		config:         cfg,
+		breaker:        cache.NewCircuitBreaker("session-store", cfg.BreakerThreshold, cfg.BreakerCooldown),
+		localBlacklist: newLocalBlacklistCache(cfg.LocalCacheSize),
+	}
+}
+
+// BreakerStats 返回缓存存储熔断器指标
+func (s *SessionService) BreakerStats() cache.BreakerStats {
+	return s.breaker.Stats()
+}
+
+// set 通过熔断器写入缓存
+func (s *SessionService) set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return s.breaker.Execute(func() error {
+		return s.store.Set(ctx, key, value, expiration)
+	})
+}
+
+// get 通过熔断器读取缓存
+func (s *SessionService) get(ctx context.Context, key string) (string, error) {
+	var value string
+	var notFound bool
+	err := s.breaker.Execute(func() error {
+		var err error
+		value, err = s.store.Get(ctx, key)
+		// 键不存在不算存储故障
+		if errors.Is(err, cache.ErrNotFound) {
+			notFound = true
+			return nil
+		}
+		return err
+	})
+	if notFound {
+		return "", cache.ErrNotFound
 	}
+	return value, err
 }
 
-// CreateSession 创建一个新的用户会话并存储到 Redis
+// del 通过熔断器删除缓存键
+func (s *SessionService) del(ctx context.Context, keys ...string) error {
+	return s.breaker.Execute(func() error {
+		return s.store.Del(ctx, keys...)
+	})
+}
+
+// exists 通过熔断器检查缓存键是否存在
+func (s *SessionService) exists(ctx context.Context, keys ...string) (int64, error) {
+	var count int64
+	err := s.breaker.Execute(func() error {
+		var err error
+		count, err = s.store.Exists(ctx, keys...)
+		return err
+	})
+	return count, err
+}
+
+// CreateSession 创建一个新的用户会话并存储到缓存
 // 会话有效期为 30 天（与刷新令牌一致）
 func (s *SessionService) CreateSession(ctx context.Context, userID uint, username, refreshToken, deviceInfo, ipAddress, userAgent string) error {
 	sessionInfo := &SessionInfo{
@@ -65,13 +142,23 @@ func (s *SessionService) CreateSession(ctx context.Context, userID uint, usernam
 	}
 
 	sessionKey := fmt.Sprintf("user:session:%d", userID)
-	return s.redisClient.Set(ctx, sessionKey, sessionData, 30*24*time.Hour)
+	if err := s.set(ctx, sessionKey, sessionData, 30*24*time.Hour); err != nil {
+		return err
+	}
+
+	// 记录到活跃会话集合，用于统计在线会话数
+	if err := s.breaker.Execute(func() error {
+		return s.store.SAdd(ctx, activeSessionsKey, userID)
+	}); err != nil {
+		sessionLog.Warn("记录活跃会话失败", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	return nil
 }
 
-// GetSession 从 Redis 获取用户会话信息
+// GetSession 从缓存获取用户会话信息
 func (s *SessionService) GetSession(ctx context.Context, userID uint) (*SessionInfo, error) {
 	sessionKey := fmt.Sprintf("user:session:%d", userID)
-	sessionData, err := s.redisClient.Get(ctx, sessionKey)
+	sessionData, err := s.get(ctx, sessionKey)
 	if err != nil {
 		return nil, fmt.Errorf("未找到会话: %w", err)
 	}
@@ -100,27 +187,47 @@ func (s *SessionService) UpdateLastActivity(ctx context.Context, userID uint) er
 	}
 
 	sessionKey := fmt.Sprintf("user:session:%d", userID)
-	return s.redisClient.Set(ctx, sessionKey, sessionData, 30*24*time.Hour)
+	return s.set(ctx, sessionKey, sessionData, 30*24*time.Hour)
 }
 
-// DeleteSession 删除 Redis 中的用户会话
+// DeleteSession 删除缓存中的用户会话
 func (s *SessionService) DeleteSession(ctx context.Context, userID uint) error {
 	sessionKey := fmt.Sprintf("user:session:%d", userID)
-	return s.redisClient.Del(ctx, sessionKey)
+	if err := s.del(ctx, sessionKey); err != nil {
+		return err
+	}
+	return s.breaker.Execute(func() error {
+		return s.store.SRem(ctx, activeSessionsKey, userID)
+	})
 }
 
-// ValidateRefreshToken 校验刷新令牌并验证 Redis 中的会话
+// CountActiveSessions 获取当前活跃会话数
+func (s *SessionService) CountActiveSessions(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.breaker.Execute(func() error {
+		var err error
+		count, err = s.store.SCard(ctx, activeSessionsKey)
+		return err
+	})
+	return count, err
+}
+
+// ValidateRefreshToken 校验刷新令牌并验证缓存中的会话
 // 步骤：
 // 1. 校验刷新令牌的有效性（JWT 格式）
 // 2. 检查是否在黑名单
-// 3. 从 Redis 获取会话并校验是否匹配
+// 3. 从缓存获取会话并校验是否匹配
 func (s *SessionService) ValidateRefreshToken(ctx context.Context, refreshToken string) (*SessionInfo, error) {
 	claims, err := s.jwtManager.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("刷新令牌无效: %w", err)
 	}
 
-	if s.IsTokenBlacklisted(ctx, claims.JTI) {
+	blacklisted, err := s.IsTokenBlacklisted(ctx, claims.JTI)
+	if err != nil {
+		return nil, err
+	}
+	if blacklisted {
 		return nil, fmt.Errorf("刷新令牌已被加入黑名单")
 	}
 
@@ -137,39 +244,73 @@ func (s *SessionService) ValidateRefreshToken(ctx context.Context, refreshToken
 }
 
 // AddTokenToBlacklist 将指定 JTI 的令牌加入黑名单（设置过期时间）
+// 同时写入本地缓存，保证本实例在缓存存储故障时依然拒绝该令牌
 func (s *SessionService) AddTokenToBlacklist(ctx context.Context, jti string, expiration time.Duration) error {
+	s.localBlacklist.Set(jti, true, expiration)
+
 	blacklistKey := fmt.Sprintf("token:blacklist:%s", jti)
-	return s.redisClient.Set(ctx, blacklistKey, "blacklisted", expiration)
+	return s.set(ctx, blacklistKey, "blacklisted", expiration)
 }
 
 // IsTokenBlacklisted 检查指定 JTI 的令牌是否在黑名单中
-func (s *SessionService) IsTokenBlacklisted(ctx context.Context, jti string) bool {
+// 查询顺序：
+// 1. 本地缓存已确认吊销 -> 直接返回 true
+// 2. 查询缓存存储，成功则刷新本地缓存
+// 3. 缓存存储不可用时使用本地缓存中最近一次的结果
+// 4. 本地也没有结果时按 FailureMode 处理：open 放行，closed 返回 ErrSessionStoreUnavailable
+func (s *SessionService) IsTokenBlacklisted(ctx context.Context, jti string) (bool, error) {
+	if blacklisted, ok := s.localBlacklist.Get(jti); ok && blacklisted {
+		return true, nil
+	}
+
 	blacklistKey := fmt.Sprintf("token:blacklist:%s", jti)
-	exists, err := s.redisClient.Exists(ctx, blacklistKey)
-	if err != nil {
-		return false
+	exists, err := s.exists(ctx, blacklistKey)
+	if err == nil {
+		s.localBlacklist.Set(jti, exists > 0, s.config.LocalCacheTTL)
+		return exists > 0, nil
 	}
-	return exists > 0
+
+	if blacklisted, ok := s.localBlacklist.Get(jti); ok {
+		sessionLog.Debug("会话存储不可用，使用本地黑名单缓存",
+			zap.String("jti", jti),
+			zap.Bool("blacklisted", blacklisted),
+			zap.Error(err))
+		return blacklisted, nil
+	}
+
+	if s.config.FailureMode == config.FailureModeClosed {
+		sessionLog.Warn("会话存储不可用，无法确认令牌状态，拒绝请求",
+			zap.String("jti", jti),
+			zap.String("failure_mode", s.config.FailureMode),
+			zap.Error(err))
+		return false, ErrSessionStoreUnavailable
+	}
+
+	sessionLog.Warn("会话存储不可用，无法确认令牌状态，按放行策略处理",
+		zap.String("jti", jti),
+		zap.String("failure_mode", s.config.FailureMode),
+		zap.Error(err))
+	return false, nil
 }
 
 // SetUserActive 设置用户为活跃状态（TTL 30 分钟）
 // 一般在用户请求时调用，用于标记在线状态
 func (s *SessionService) SetUserActive(ctx context.Context, userID uint) error {
 	activeKey := fmt.Sprintf("user:active:%d", userID)
-	return s.redisClient.Set(ctx, activeKey, time.Now().Unix(), 30*time.Minute)
+	return s.set(ctx, activeKey, time.Now().Unix(), 30*time.Minute)
 }
 
 // IsUserActive 判断用户当前是否处于活跃状态
 func (s *SessionService) IsUserActive(ctx context.Context, userID uint) bool {
 	activeKey := fmt.Sprintf("user:active:%d", userID)
-	exists, err := s.redisClient.Exists(ctx, activeKey)
+	exists, err := s.exists(ctx, activeKey)
 	if err != nil {
 		return false
 	}
 	return exists > 0
 }
 
-// CacheUserPermissions 缓存用户角色和权限到 Redis
+// CacheUserPermissions 缓存用户角色和权限
 // 缓存内容包括：角色、权限列表、缓存时间
 // TTL 默认 1 小时
 func (s *SessionService) CacheUserPermissions(ctx context.Context, userID uint, role string, permissions []string) error {
@@ -185,14 +326,14 @@ func (s *SessionService) CacheUserPermissions(ctx context.Context, userID uint,
 	}
 
 	permissionKey := fmt.Sprintf("user:permissions:%d", userID)
-	return s.redisClient.Set(ctx, permissionKey, data, time.Hour)
+	return s.set(ctx, permissionKey, data, time.Hour)
 }
 
 // GetCachedUserPermissions 获取缓存的用户权限
 // 返回用户的角色和权限列表
 func (s *SessionService) GetCachedUserPermissions(ctx context.Context, userID uint) (string, []string, error) {
 	permissionKey := fmt.Sprintf("user:permissions:%d", userID)
-	data, err := s.redisClient.Get(ctx, permissionKey)
+	data, err := s.get(ctx, permissionKey)
 	if err != nil {
 		return "", nil, fmt.Errorf("权限未缓存: %w", err)
 	}
@@ -213,9 +354,86 @@ func (s *SessionService) GetCachedUserPermissions(ctx context.Context, userID ui
 	return role, permissions, nil
 }
 
-// CleanupExpiredSessions 清理过期会话（占位方法）
-// 实际上 Redis TTL 已经能自动清理大部分过期会话
-// 可以作为定时任务扩展，用于更复杂的清理逻辑
+// StartCleanup 启动后台任务，按 CleanupInterval 定期调用 CleanupExpiredSessions
+// 返回的函数停止后台任务，可注册为关闭钩子
+func (s *SessionService) StartCleanup() func(ctx context.Context) error {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(s.config.CleanupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), s.config.CleanupInterval)
+				if err := s.CleanupExpiredSessions(ctx); err != nil {
+					sessionLog.Warn("清理过期会话失败", zap.Error(err))
+				}
+				cancel()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func(ctx context.Context) error {
+		once.Do(func() { close(stop) })
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// CleanupExpiredSessions 清理活跃会话集合中已过期的会话
+// 会话本身由缓存 TTL 自动过期，这里只移除集合中残留的用户ID，保证活跃会话数准确
+// 由 StartCleanup 定期调用
 func (s *SessionService) CleanupExpiredSessions(ctx context.Context) error {
+	var members []string
+	if err := s.breaker.Execute(func() error {
+		var err error
+		members, err = s.store.SMembers(ctx, activeSessionsKey)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// 分批检查会话键是否存在，每批一次往返
+	for start := 0; start < len(members); start += sessionCleanupBatchSize {
+		batch := members[start:min(start+sessionCleanupBatchSize, len(members))]
+		keys := make([]string, len(batch))
+		for i, member := range batch {
+			keys[i] = "user:session:" + member
+		}
+
+		var exists []bool
+		if err := s.breaker.Execute(func() error {
+			var err error
+			exists, err = s.store.ExistsEach(ctx, keys...)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		var expired []interface{}
+		for i, ok := range exists {
+			if !ok {
+				expired = append(expired, batch[i])
+			}
+		}
+		if len(expired) == 0 {
+			continue
+		}
+		if err := s.breaker.Execute(func() error {
+			return s.store.SRem(ctx, activeSessionsKey, expired...)
+		}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
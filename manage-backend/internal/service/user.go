@@ -9,23 +9,39 @@ import (
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/query"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+// tracer 服务层链路追踪
+var tracer = otel.Tracer("github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service")
+
+// userLog 用户服务日志器，可通过 logger.SetLevel("service.user", ...) 单独调整级别
+var userLog = logger.Component("service.user")
+
 // UserRepositoryInterface 定义用户仓库接口
 type UserRepositoryInterface interface {
-	Create(user *model.User) error
-	GetByID(id uint) (*model.User, error)
-	GetByUsername(username string) (*model.User, error)
-	GetByEmail(email string) (*model.User, error)
-	Update(user *model.User) error
-	Delete(id uint) error
-	List(offset, limit int) ([]model.User, int64, error)
-	CheckUsernameExists(username string) (bool, error)
-	CheckEmailExists(email string) (bool, error)
-	CheckUsernameExistsExcludeID(username string, excludeID uint) (bool, error)
-	CheckEmailExistsExcludeID(email string, excludeID uint) (bool, error)
+	Create(ctx context.Context, user *model.User) error
+	CreateBatch(ctx context.Context, users []*model.User, batchSize int) error
+	GetByID(ctx context.Context, id uint) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetCredentials(ctx context.Context, username string) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	Update(ctx context.Context, user *model.User) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int, spec query.Spec) ([]model.User, int64, error)
+	ListCursor(ctx context.Context, req query.CursorRequest, spec query.Spec) (*query.CursorPage[model.User], error)
+	Count(ctx context.Context, spec query.Spec) (int64, error)
+	Stream(ctx context.Context, spec query.Spec, fn func(*model.User) error) error
+	CheckUsernameExists(ctx context.Context, username string) (bool, error)
+	CheckEmailExists(ctx context.Context, email string) (bool, error)
+	CheckUsernameExistsExcludeID(ctx context.Context, username string, excludeID uint) (bool, error)
+	CheckEmailExistsExcludeID(ctx context.Context, email string, excludeID uint) (bool, error)
+	FindExistingUsernames(ctx context.Context, usernames []string) ([]string, error)
+	FindExistingEmails(ctx context.Context, emails []string) ([]string, error)
 }
 
 // JWTManagerInterface 定义 JWT 管理器接口
@@ -45,7 +61,7 @@ type SessionServiceInterface interface {
 	DeleteSession(ctx context.Context, userID uint) error
 	ValidateRefreshToken(ctx context.Context, refreshToken string) (*SessionInfo, error)
 	AddTokenToBlacklist(ctx context.Context, jti string, expiration time.Duration) error
-	IsTokenBlacklisted(ctx context.Context, jti string) bool
+	IsTokenBlacklisted(ctx context.Context, jti string) (bool, error)
 	SetUserActive(ctx context.Context, userID uint) error
 	CacheUserPermissions(ctx context.Context, userID uint, role string, permissions []string) error
 }
@@ -66,25 +82,28 @@ func NewUserService(userRepo UserRepositoryInterface, jwtManager JWTManagerInter
 	}
 }
 
-func (s *UserService) Register(req *model.CreateUserRequest) (*model.User, error) {
-	logger.Info("开始用户注册流程", 
+func (s *UserService) Register(ctx context.Context, req *model.CreateUserRequest) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Register")
+	defer span.End()
+
+	userLog.InfoContext(ctx, "开始用户注册流程", 
 		zap.String("username", req.Username),
 		zap.String("email", req.Email),
 		zap.String("role", req.Role))
 
 	// 检查用户名是否已存在
-	_, err := s.userRepo.GetByUsername(req.Username)
+	_, err := s.userRepo.GetByUsername(ctx, req.Username)
 	if err == nil {
-		logger.Warn("用户注册失败：用户名已存在", 
+		userLog.WarnContext(ctx, "用户注册失败：用户名已存在", 
 			zap.String("username", req.Username),
 			zap.String("operation", "register"))
 		return nil, errors.New("username already exists")
 	}
 
 	// 检查邮箱是否已存在
-	_, err = s.userRepo.GetByEmail(req.Email)
+	_, err = s.userRepo.GetByEmail(ctx, req.Email)
 	if err == nil {
-		logger.Warn("用户注册失败：邮箱已存在", 
+		userLog.WarnContext(ctx, "用户注册失败：邮箱已存在", 
 			zap.String("username", req.Username),
 			zap.String("email", req.Email),
 			zap.String("operation", "register"))
@@ -94,7 +113,7 @@ func (s *UserService) Register(req *model.CreateUserRequest) (*model.User, error
 	// 加密密码
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
-		logger.Error("密码加密失败", 
+		userLog.ErrorContext(ctx, "密码加密失败", 
 			zap.String("username", req.Username),
 			zap.Error(err),
 			zap.String("operation", "register"))
@@ -110,14 +129,14 @@ func (s *UserService) Register(req *model.CreateUserRequest) (*model.User, error
 
 	if user.Role == "" {
 		user.Role = "user"
-		logger.Debug("用户角色为空，设置为默认角色", 
+		userLog.DebugContext(ctx, "用户角色为空，设置为默认角色", 
 			zap.String("username", req.Username),
 			zap.String("default_role", "user"))
 	}
 
-	err = s.userRepo.Create(user)
+	err = s.userRepo.Create(ctx, user)
 	if err != nil {
-		logger.Error("用户创建失败", 
+		userLog.ErrorContext(ctx, "用户创建失败", 
 			zap.String("username", req.Username),
 			zap.String("email", req.Email),
 			zap.Error(err),
@@ -125,7 +144,7 @@ func (s *UserService) Register(req *model.CreateUserRequest) (*model.User, error
 		return nil, err
 	}
 
-	logger.Info("用户注册成功", 
+	userLog.InfoContext(ctx, "用户注册成功", 
 		zap.String("username", user.Username),
 		zap.Uint("user_id", user.ID),
 		zap.String("role", user.Role),
@@ -140,7 +159,10 @@ func (s *UserService) Login(req *model.LoginRequest) (*model.LoginResponse, erro
 
 // LoginWithContext 带会话上下文信息的登录
 func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequest, deviceInfo, ipAddress, userAgent string) (*model.LoginResponse, error) {
-	logger.Info("开始用户登录流程", 
+	ctx, span := tracer.Start(ctx, "UserService.Login")
+	defer span.End()
+
+	userLog.InfoContext(ctx, "开始用户登录流程", 
 		zap.String("username", req.Username),
 		zap.String("ip_address", ipAddress),
 		zap.String("user_agent", userAgent),
@@ -149,44 +171,48 @@ func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequ
 	// 验证验证码
 	if s.captchaService != nil {
 		if !s.captchaService.VerifyCaptcha(req.CaptchaID, req.CaptchaCode) {
-			logger.Warn("登录失败：验证码错误", 
+			userLog.WarnContext(ctx, "登录失败：验证码错误", 
 				zap.String("username", req.Username),
 				zap.String("captcha_id", req.CaptchaID),
 				zap.String("ip_address", ipAddress),
 				zap.String("operation", "login"))
+			metrics.RecordLogin(metrics.LoginFailure, metrics.LoginReasonInvalidCaptcha)
 			return nil, errors.New("invalid captcha")
 		}
-		logger.Debug("验证码验证通过", 
+		userLog.DebugContext(ctx, "验证码验证通过", 
 			zap.String("username", req.Username),
 			zap.String("captcha_id", req.CaptchaID))
 	}
 
-	user, err := s.userRepo.GetByUsername(req.Username)
+	user, err := s.userRepo.GetCredentials(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Warn("登录失败：用户不存在", 
+			userLog.WarnContext(ctx, "登录失败：用户不存在", 
 				zap.String("username", req.Username),
 				zap.String("ip_address", ipAddress),
 				zap.String("operation", "login"))
+			metrics.RecordLogin(metrics.LoginFailure, metrics.LoginReasonUserNotFound)
 			return nil, errors.New("invalid credentials")
 		}
-		logger.Error("登录失败：查询用户时发生错误", 
+		userLog.ErrorContext(ctx, "登录失败：查询用户时发生错误", 
 			zap.String("username", req.Username),
 			zap.Error(err),
 			zap.String("operation", "login"))
+		metrics.RecordLogin(metrics.LoginFailure, metrics.LoginReasonInternalError)
 		return nil, err
 	}
 
 	if !utils.CheckPassword(req.Password, user.Password) {
-		logger.Warn("登录失败：密码错误", 
+		userLog.WarnContext(ctx, "登录失败：密码错误", 
 			zap.String("username", req.Username),
 			zap.Uint("user_id", user.ID),
 			zap.String("ip_address", ipAddress),
 			zap.String("operation", "login"))
+		metrics.RecordLogin(metrics.LoginFailure, metrics.LoginReasonInvalidPassword)
 		return nil, errors.New("invalid credentials")
 	}
 
-	logger.Debug("用户认证成功", 
+	userLog.DebugContext(ctx, "用户认证成功", 
 		zap.String("username", user.Username),
 		zap.Uint("user_id", user.ID),
 		zap.String("role", user.Role))
@@ -194,11 +220,12 @@ func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequ
 	// 生成令牌对
 	tokenPair, err := s.jwtManager.GenerateTokenPair(user.ID, user.Username, user.Role)
 	if err != nil {
-		logger.Error("生成令牌失败", 
+		userLog.ErrorContext(ctx, "生成令牌失败", 
 			zap.String("username", user.Username),
 			zap.Uint("user_id", user.ID),
 			zap.Error(err),
 			zap.String("operation", "login"))
+		metrics.RecordLogin(metrics.LoginFailure, metrics.LoginReasonInternalError)
 		return nil, err
 	}
 
@@ -206,11 +233,12 @@ func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequ
 	if s.sessionService != nil {
 		err = s.sessionService.CreateSession(ctx, user.ID, user.Username, tokenPair.RefreshToken, deviceInfo, ipAddress, userAgent)
 		if err != nil {
-			logger.Error("创建会话失败", 
+			userLog.ErrorContext(ctx, "创建会话失败", 
 				zap.String("username", user.Username),
 				zap.Uint("user_id", user.ID),
 				zap.Error(err),
 				zap.String("operation", "login"))
+			metrics.RecordLogin(metrics.LoginFailure, metrics.LoginReasonInternalError)
 			return nil, err
 		}
 
@@ -221,7 +249,7 @@ func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequ
 		permissions := []string{} // 可根据权限系统扩展
 		s.sessionService.CacheUserPermissions(ctx, user.ID, user.Role, permissions)
 		
-		logger.Debug("会话创建成功", 
+		userLog.DebugContext(ctx, "会话创建成功", 
 			zap.String("username", user.Username),
 			zap.Uint("user_id", user.ID))
 	}
@@ -237,13 +265,15 @@ func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequ
 		UpdatedAt: user.UpdatedAt,
 	}
 
-	logger.Info("用户登录成功", 
+	userLog.InfoContext(ctx, "用户登录成功", 
 		zap.String("username", user.Username),
 		zap.Uint("user_id", user.ID),
 		zap.String("role", user.Role),
 		zap.String("ip_address", ipAddress),
 		zap.String("operation", "login"))
 
+	metrics.RecordLogin(metrics.LoginSuccess, metrics.LoginReasonNone)
+
 	return &model.LoginResponse{
 		AccessToken:      tokenPair.AccessToken,
 		RefreshToken:     tokenPair.RefreshToken,
@@ -256,10 +286,13 @@ func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequ
 
 // RefreshToken 使用刷新令牌更新访问令牌
 func (s *UserService) RefreshToken(ctx context.Context, req *model.RefreshTokenRequest) (*model.RefreshTokenResponse, error) {
-	logger.Debug("开始刷新令牌流程")
+	ctx, span := tracer.Start(ctx, "UserService.RefreshToken")
+	defer span.End()
+
+	userLog.DebugContext(ctx, "开始刷新令牌流程")
 
 	if s.sessionService == nil {
-		logger.Error("刷新令牌失败：会话服务不可用", 
+		userLog.ErrorContext(ctx, "刷新令牌失败：会话服务不可用", 
 			zap.String("operation", "refresh_token"))
 		return nil, errors.New("session service not available")
 	}
@@ -267,20 +300,20 @@ func (s *UserService) RefreshToken(ctx context.Context, req *model.RefreshTokenR
 	// 验证刷新令牌并获取会话
 	sessionInfo, err := s.sessionService.ValidateRefreshToken(ctx, req.RefreshToken)
 	if err != nil {
-		logger.Warn("刷新令牌失败：无效的刷新令牌", 
+		userLog.WarnContext(ctx, "刷新令牌失败：无效的刷新令牌", 
 			zap.Error(err),
 			zap.String("operation", "refresh_token"))
 		return nil, errors.New("invalid refresh token")
 	}
 
-	logger.Debug("刷新令牌验证成功", 
+	userLog.DebugContext(ctx, "刷新令牌验证成功", 
 		zap.String("username", sessionInfo.Username),
 		zap.Uint("user_id", sessionInfo.UserID))
 
 	// 生成新的令牌对
 	tokenPair, err := s.jwtManager.GenerateTokenPair(sessionInfo.UserID, sessionInfo.Username, "user") // 角色可从会话中获取
 	if err != nil {
-		logger.Error("生成新令牌失败", 
+		userLog.ErrorContext(ctx, "生成新令牌失败", 
 			zap.String("username", sessionInfo.Username),
 			zap.Uint("user_id", sessionInfo.UserID),
 			zap.Error(err),
@@ -291,7 +324,7 @@ func (s *UserService) RefreshToken(ctx context.Context, req *model.RefreshTokenR
 	// 用新的刷新令牌更新会话
 	err = s.sessionService.CreateSession(ctx, sessionInfo.UserID, sessionInfo.Username, tokenPair.RefreshToken, sessionInfo.DeviceInfo, sessionInfo.IPAddress, sessionInfo.UserAgent)
 	if err != nil {
-		logger.Error("更新会话失败", 
+		userLog.ErrorContext(ctx, "更新会话失败", 
 			zap.String("username", sessionInfo.Username),
 			zap.Uint("user_id", sessionInfo.UserID),
 			zap.Error(err),
@@ -302,7 +335,7 @@ func (s *UserService) RefreshToken(ctx context.Context, req *model.RefreshTokenR
 	// 更新最后活跃时间
 	s.sessionService.UpdateLastActivity(ctx, sessionInfo.UserID)
 
-	logger.Info("令牌刷新成功", 
+	userLog.InfoContext(ctx, "令牌刷新成功", 
 		zap.String("username", sessionInfo.Username),
 		zap.Uint("user_id", sessionInfo.UserID),
 		zap.String("operation", "refresh_token"))
@@ -316,12 +349,15 @@ func (s *UserService) RefreshToken(ctx context.Context, req *model.RefreshTokenR
 
 // Logout 用户登出
 func (s *UserService) Logout(ctx context.Context, userID uint, accessToken string, req *model.LogoutRequest) error {
-	logger.Info("开始用户登出流程", 
+	ctx, span := tracer.Start(ctx, "UserService.Logout")
+	defer span.End()
+
+	userLog.InfoContext(ctx, "开始用户登出流程", 
 		zap.Uint("user_id", userID),
 		zap.String("operation", "logout"))
 
 	if s.sessionService == nil {
-		logger.Error("登出失败：会话服务不可用", 
+		userLog.ErrorContext(ctx, "登出失败：会话服务不可用", 
 			zap.Uint("user_id", userID),
 			zap.String("operation", "logout"))
 		return errors.New("session service not available")
@@ -330,14 +366,14 @@ func (s *UserService) Logout(ctx context.Context, userID uint, accessToken strin
 	// 验证并获取访问令牌声明
 	claims, err := s.jwtManager.ValidateToken(accessToken)
 	if err != nil {
-		logger.Warn("登出失败：无效的访问令牌", 
+		userLog.WarnContext(ctx, "登出失败：无效的访问令牌", 
 			zap.Uint("user_id", userID),
 			zap.Error(err),
 			zap.String("operation", "logout"))
 		return errors.New("invalid access token")
 	}
 
-	logger.Debug("访问令牌验证成功", 
+	userLog.DebugContext(ctx, "访问令牌验证成功", 
 		zap.Uint("user_id", userID),
 		zap.String("jti", claims.JTI))
 
@@ -346,14 +382,14 @@ func (s *UserService) Logout(ctx context.Context, userID uint, accessToken strin
 	if expiration > 0 {
 		err = s.sessionService.AddTokenToBlacklist(ctx, claims.JTI, expiration)
 		if err != nil {
-			logger.Error("添加访问令牌到黑名单失败", 
+			userLog.ErrorContext(ctx, "添加访问令牌到黑名单失败", 
 				zap.Uint("user_id", userID),
 				zap.String("jti", claims.JTI),
 				zap.Error(err),
 				zap.String("operation", "logout"))
 			return err
 		}
-		logger.Debug("访问令牌已加入黑名单", 
+		userLog.DebugContext(ctx, "访问令牌已加入黑名单", 
 			zap.Uint("user_id", userID),
 			zap.String("jti", claims.JTI))
 	}
@@ -365,12 +401,12 @@ func (s *UserService) Logout(ctx context.Context, userID uint, accessToken strin
 			refreshExpiration := s.jwtManager.GetTokenExpiration(refreshClaims)
 			if refreshExpiration > 0 {
 				s.sessionService.AddTokenToBlacklist(ctx, refreshClaims.JTI, refreshExpiration)
-				logger.Debug("刷新令牌已加入黑名单", 
+				userLog.DebugContext(ctx, "刷新令牌已加入黑名单", 
 					zap.Uint("user_id", userID),
 					zap.String("refresh_jti", refreshClaims.JTI))
 			}
 		} else {
-			logger.Warn("刷新令牌验证失败", 
+			userLog.WarnContext(ctx, "刷新令牌验证失败", 
 				zap.Uint("user_id", userID),
 				zap.Error(err))
 		}
@@ -379,33 +415,36 @@ func (s *UserService) Logout(ctx context.Context, userID uint, accessToken strin
 	// 删除会话
 	err = s.sessionService.DeleteSession(ctx, userID)
 	if err != nil {
-		logger.Error("删除会话失败", 
+		userLog.ErrorContext(ctx, "删除会话失败", 
 			zap.Uint("user_id", userID),
 			zap.Error(err),
 			zap.String("operation", "logout"))
 		return err
 	}
 
-	logger.Info("用户登出成功", 
+	userLog.InfoContext(ctx, "用户登出成功", 
 		zap.Uint("user_id", userID),
 		zap.String("operation", "logout"))
 
 	return nil
 }
 
-func (s *UserService) GetByID(id uint) (*model.User, error) {
-	logger.Debug("查询用户信息", 
+func (s *UserService) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetByID")
+	defer span.End()
+
+	userLog.DebugContext(ctx, "查询用户信息", 
 		zap.Uint("user_id", id),
 		zap.String("operation", "get_user"))
 
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Warn("用户不存在", 
+			userLog.WarnContext(ctx, "用户不存在", 
 				zap.Uint("user_id", id),
 				zap.String("operation", "get_user"))
 		} else {
-			logger.Error("查询用户失败", 
+			userLog.ErrorContext(ctx, "查询用户失败", 
 				zap.Uint("user_id", id),
 				zap.Error(err),
 				zap.String("operation", "get_user"))
@@ -413,7 +452,7 @@ func (s *UserService) GetByID(id uint) (*model.User, error) {
 		return nil, err
 	}
 
-	logger.Debug("用户查询成功", 
+	userLog.DebugContext(ctx, "用户查询成功", 
 		zap.Uint("user_id", id),
 		zap.String("username", user.Username),
 		zap.String("operation", "get_user"))
@@ -421,19 +460,22 @@ func (s *UserService) GetByID(id uint) (*model.User, error) {
 	return user, nil
 }
 
-func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User, error) {
-	logger.Info("开始更新用户信息", 
+func (s *UserService) Update(ctx context.Context, id uint, req *model.UpdateUserRequest) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Update")
+	defer span.End()
+
+	userLog.InfoContext(ctx, "开始更新用户信息", 
 		zap.Uint("user_id", id),
 		zap.String("operation", "update_user"))
 
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Warn("更新失败：用户不存在", 
+			userLog.WarnContext(ctx, "更新失败：用户不存在", 
 				zap.Uint("user_id", id),
 				zap.String("operation", "update_user"))
 		} else {
-			logger.Error("查询用户失败", 
+			userLog.ErrorContext(ctx, "查询用户失败", 
 				zap.Uint("user_id", id),
 				zap.Error(err),
 				zap.String("operation", "update_user"))
@@ -444,7 +486,7 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 	// 记录更新的字段
 	updatedFields := []string{}
 	if req.Username != "" {
-		logger.Debug("更新用户名", 
+		userLog.DebugContext(ctx, "更新用户名", 
 			zap.Uint("user_id", id),
 			zap.String("old_username", user.Username),
 			zap.String("new_username", req.Username))
@@ -452,7 +494,7 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 		updatedFields = append(updatedFields, "username")
 	}
 	if req.Email != "" {
-		logger.Debug("更新邮箱", 
+		userLog.DebugContext(ctx, "更新邮箱", 
 			zap.Uint("user_id", id),
 			zap.String("old_email", user.Email),
 			zap.String("new_email", req.Email))
@@ -460,7 +502,7 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 		updatedFields = append(updatedFields, "email")
 	}
 	if req.Role != "" {
-		logger.Debug("更新角色", 
+		userLog.DebugContext(ctx, "更新角色", 
 			zap.Uint("user_id", id),
 			zap.String("old_role", user.Role),
 			zap.String("new_role", req.Role))
@@ -468,7 +510,7 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 		updatedFields = append(updatedFields, "role")
 	}
 	if req.Status != "" {
-		logger.Debug("更新状态", 
+		userLog.DebugContext(ctx, "更新状态", 
 			zap.Uint("user_id", id),
 			zap.String("old_status", user.Status),
 			zap.String("new_status", req.Status))
@@ -476,9 +518,9 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 		updatedFields = append(updatedFields, "status")
 	}
 
-	err = s.userRepo.Update(user)
+	err = s.userRepo.Update(ctx, user)
 	if err != nil {
-		logger.Error("用户更新失败", 
+		userLog.ErrorContext(ctx, "用户更新失败", 
 			zap.Uint("user_id", id),
 			zap.Strings("updated_fields", updatedFields),
 			zap.Error(err),
@@ -486,7 +528,7 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 		return nil, err
 	}
 
-	logger.Info("用户更新成功", 
+	userLog.InfoContext(ctx, "用户更新成功", 
 		zap.Uint("user_id", id),
 		zap.String("username", user.Username),
 		zap.Strings("updated_fields", updatedFields),
@@ -495,20 +537,23 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 	return user, nil
 }
 
-func (s *UserService) Delete(id uint) error {
-	logger.Info("开始删除用户", 
+func (s *UserService) Delete(ctx context.Context, id uint) error {
+	ctx, span := tracer.Start(ctx, "UserService.Delete")
+	defer span.End()
+
+	userLog.InfoContext(ctx, "开始删除用户", 
 		zap.Uint("user_id", id),
 		zap.String("operation", "delete_user"))
 
 	// 先查询用户信息用于日志记录
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Warn("删除失败：用户不存在", 
+			userLog.WarnContext(ctx, "删除失败：用户不存在", 
 				zap.Uint("user_id", id),
 				zap.String("operation", "delete_user"))
 		} else {
-			logger.Error("查询用户失败", 
+			userLog.ErrorContext(ctx, "查询用户失败", 
 				zap.Uint("user_id", id),
 				zap.Error(err),
 				zap.String("operation", "delete_user"))
@@ -516,9 +561,9 @@ func (s *UserService) Delete(id uint) error {
 		return err
 	}
 
-	err = s.userRepo.Delete(id)
+	err = s.userRepo.Delete(ctx, id)
 	if err != nil {
-		logger.Error("用户删除失败", 
+		userLog.ErrorContext(ctx, "用户删除失败", 
 			zap.Uint("user_id", id),
 			zap.String("username", user.Username),
 			zap.Error(err),
@@ -526,7 +571,7 @@ func (s *UserService) Delete(id uint) error {
 		return err
 	}
 
-	logger.Info("用户删除成功", 
+	userLog.InfoContext(ctx, "用户删除成功", 
 		zap.Uint("user_id", id),
 		zap.String("username", user.Username),
 		zap.String("operation", "delete_user"))
@@ -534,16 +579,20 @@ func (s *UserService) Delete(id uint) error {
 	return nil
 }
 
-func (s *UserService) List(page, pageSize int) ([]model.User, int64, error) {
-	logger.Debug("查询用户列表", 
+func (s *UserService) List(ctx context.Context, page, pageSize int, spec query.Spec) ([]model.User, int64, error) {
+	ctx, span := tracer.Start(ctx, "UserService.List")
+	defer span.End()
+
+	userLog.DebugContext(ctx, "查询用户列表", 
 		zap.Int("page", page),
 		zap.Int("page_size", pageSize),
+		zap.Int("filters", len(spec.Filters)),
 		zap.String("operation", "list_users"))
 
 	offset := (page - 1) * pageSize
-	users, total, err := s.userRepo.List(offset, pageSize)
+	users, total, err := s.userRepo.List(ctx, offset, pageSize, spec)
 	if err != nil {
-		logger.Error("查询用户列表失败", 
+		userLog.ErrorContext(ctx, "查询用户列表失败", 
 			zap.Int("page", page),
 			zap.Int("page_size", pageSize),
 			zap.Error(err),
@@ -551,7 +600,7 @@ func (s *UserService) List(page, pageSize int) ([]model.User, int64, error) {
 		return nil, 0, err
 	}
 
-	logger.Debug("用户列表查询成功", 
+	userLog.DebugContext(ctx, "用户列表查询成功", 
 		zap.Int("page", page),
 		zap.Int("page_size", pageSize),
 		zap.Int64("total", total),
@@ -561,15 +610,40 @@ func (s *UserService) List(page, pageSize int) ([]model.User, int64, error) {
 	return users, total, nil
 }
 
+// ListCursor 按游标分页查询用户列表
+func (s *UserService) ListCursor(ctx context.Context, req query.CursorRequest, spec query.Spec) (*query.CursorPage[model.User], error) {
+	ctx, span := tracer.Start(ctx, "UserService.ListCursor")
+	defer span.End()
+
+	page, err := s.userRepo.ListCursor(ctx, req, spec)
+	if err != nil {
+		userLog.ErrorContext(ctx, "查询用户列表失败",
+			zap.Int("page_size", req.Limit),
+			zap.Bool("has_cursor", req.Cursor != ""),
+			zap.Error(err),
+			zap.String("operation", "list_users"))
+		return nil, err
+	}
+
+	userLog.DebugContext(ctx, "用户列表查询成功",
+		zap.Int("page_size", req.Limit),
+		zap.Bool("has_cursor", req.Cursor != ""),
+		zap.Int64("total", page.Total),
+		zap.Int("returned_count", len(page.Items)),
+		zap.String("operation", "list_users"))
+
+	return page, nil
+}
+
 // CheckUsernameAvailable 检查用户名是否可用
-func (s *UserService) CheckUsernameAvailable(username string) (bool, error) {
-	logger.Debug("检查用户名可用性", 
+func (s *UserService) CheckUsernameAvailable(ctx context.Context, username string) (bool, error) {
+	userLog.DebugContext(ctx, "检查用户名可用性", 
 		zap.String("username", username),
 		zap.String("operation", "check_username"))
 
-	exists, err := s.userRepo.CheckUsernameExists(username)
+	exists, err := s.userRepo.CheckUsernameExists(ctx, username)
 	if err != nil {
-		logger.Error("检查用户名可用性失败", 
+		userLog.ErrorContext(ctx, "检查用户名可用性失败", 
 			zap.String("username", username),
 			zap.Error(err),
 			zap.String("operation", "check_username"))
@@ -577,7 +651,7 @@ func (s *UserService) CheckUsernameAvailable(username string) (bool, error) {
 	}
 
 	available := !exists
-	logger.Debug("用户名可用性检查完成", 
+	userLog.DebugContext(ctx, "用户名可用性检查完成", 
 		zap.String("username", username),
 		zap.Bool("available", available),
 		zap.String("operation", "check_username"))
@@ -586,14 +660,14 @@ func (s *UserService) CheckUsernameAvailable(username string) (bool, error) {
 }
 
 // CheckEmailAvailable 检查邮箱是否可用
-func (s *UserService) CheckEmailAvailable(email string) (bool, error) {
-	logger.Debug("检查邮箱可用性", 
+func (s *UserService) CheckEmailAvailable(ctx context.Context, email string) (bool, error) {
+	userLog.DebugContext(ctx, "检查邮箱可用性", 
 		zap.String("email", email),
 		zap.String("operation", "check_email"))
 
-	exists, err := s.userRepo.CheckEmailExists(email)
+	exists, err := s.userRepo.CheckEmailExists(ctx, email)
 	if err != nil {
-		logger.Error("检查邮箱可用性失败", 
+		userLog.ErrorContext(ctx, "检查邮箱可用性失败", 
 			zap.String("email", email),
 			zap.Error(err),
 			zap.String("operation", "check_email"))
@@ -601,7 +675,7 @@ func (s *UserService) CheckEmailAvailable(email string) (bool, error) {
 	}
 
 	available := !exists
-	logger.Debug("邮箱可用性检查完成", 
+	userLog.DebugContext(ctx, "邮箱可用性检查完成", 
 		zap.String("email", email),
 		zap.Bool("available", available),
 		zap.String("operation", "check_email"))
@@ -610,8 +684,8 @@ func (s *UserService) CheckEmailAvailable(email string) (bool, error) {
 }
 
 // CheckUserDataAvailability 批量检查用户数据可用性
-func (s *UserService) CheckUserDataAvailability(req *model.CheckAvailabilityRequest) (*model.CheckAvailabilityResponse, error) {
-	logger.Debug("开始批量检查用户数据可用性", 
+func (s *UserService) CheckUserDataAvailability(ctx context.Context, req *model.CheckAvailabilityRequest) (*model.CheckAvailabilityResponse, error) {
+	userLog.DebugContext(ctx, "开始批量检查用户数据可用性", 
 		zap.String("username", req.Username),
 		zap.String("email", req.Email),
 		zap.Any("exclude_user_id", req.ExcludeUserID),
@@ -625,12 +699,12 @@ func (s *UserService) CheckUserDataAvailability(req *model.CheckAvailabilityRequ
 		var err error
 		
 		if req.ExcludeUserID != nil && *req.ExcludeUserID > 0 {
-			logger.Debug("检查用户名可用性（排除指定用户）", 
+			userLog.DebugContext(ctx, "检查用户名可用性（排除指定用户）", 
 				zap.String("username", req.Username),
 				zap.Uint("exclude_user_id", *req.ExcludeUserID))
-			exists, err := s.userRepo.CheckUsernameExistsExcludeID(req.Username, *req.ExcludeUserID)
+			exists, err := s.userRepo.CheckUsernameExistsExcludeID(ctx, req.Username, *req.ExcludeUserID)
 			if err != nil {
-				logger.Error("检查用户名可用性失败", 
+				userLog.ErrorContext(ctx, "检查用户名可用性失败", 
 					zap.String("username", req.Username),
 					zap.Uint("exclude_user_id", *req.ExcludeUserID),
 					zap.Error(err),
@@ -639,7 +713,7 @@ func (s *UserService) CheckUserDataAvailability(req *model.CheckAvailabilityRequ
 			}
 			available = !exists
 		} else {
-			available, err = s.CheckUsernameAvailable(req.Username)
+			available, err = s.CheckUsernameAvailable(ctx, req.Username)
 			if err != nil {
 				return nil, err
 			}
@@ -655,7 +729,7 @@ func (s *UserService) CheckUserDataAvailability(req *model.CheckAvailabilityRequ
 			Message:   message,
 		}
 
-		logger.Debug("用户名可用性检查结果", 
+		userLog.DebugContext(ctx, "用户名可用性检查结果", 
 			zap.String("username", req.Username),
 			zap.Bool("available", available))
 	}
@@ -666,12 +740,12 @@ func (s *UserService) CheckUserDataAvailability(req *model.CheckAvailabilityRequ
 		var err error
 		
 		if req.ExcludeUserID != nil && *req.ExcludeUserID > 0 {
-			logger.Debug("检查邮箱可用性（排除指定用户）", 
+			userLog.DebugContext(ctx, "检查邮箱可用性（排除指定用户）", 
 				zap.String("email", req.Email),
 				zap.Uint("exclude_user_id", *req.ExcludeUserID))
-			exists, err := s.userRepo.CheckEmailExistsExcludeID(req.Email, *req.ExcludeUserID)
+			exists, err := s.userRepo.CheckEmailExistsExcludeID(ctx, req.Email, *req.ExcludeUserID)
 			if err != nil {
-				logger.Error("检查邮箱可用性失败", 
+				userLog.ErrorContext(ctx, "检查邮箱可用性失败", 
 					zap.String("email", req.Email),
 					zap.Uint("exclude_user_id", *req.ExcludeUserID),
 					zap.Error(err),
@@ -680,7 +754,7 @@ func (s *UserService) CheckUserDataAvailability(req *model.CheckAvailabilityRequ
 			}
 			available = !exists
 		} else {
-			available, err = s.CheckEmailAvailable(req.Email)
+			available, err = s.CheckEmailAvailable(ctx, req.Email)
 			if err != nil {
 				return nil, err
 			}
@@ -696,12 +770,12 @@ func (s *UserService) CheckUserDataAvailability(req *model.CheckAvailabilityRequ
 			Message:   message,
 		}
 
-		logger.Debug("邮箱可用性检查结果", 
+		userLog.DebugContext(ctx, "邮箱可用性检查结果", 
 			zap.String("email", req.Email),
 			zap.Bool("available", available))
 	}
 
-	logger.Debug("批量检查用户数据可用性完成", 
+	userLog.DebugContext(ctx, "批量检查用户数据可用性完成", 
 		zap.String("operation", "check_availability"))
 
 	return response, nil
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/export"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/jobs"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/query"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidExport 导出参数不合法（未知的列、格式等），调用方应返回 400
+var ErrInvalidExport = errors.New("invalid export request")
+
+// userExportJobKind 用户导出任务类型
+const userExportJobKind = "user_export"
+
+// exportProgressInterval 每处理多少条记录报告一次进度
+const exportProgressInterval = 500
+
+// userExportColumn 可导出的用户列，表头按语言区分
+type userExportColumn struct {
+	key     string
+	headers map[string]string
+	value   func(u *model.UserResponse) interface{}
+}
+
+// userExportColumns 用户导出的全部列，未指定列时按此顺序导出
+var userExportColumns = []userExportColumn{
+	{key: "id", headers: map[string]string{"zh": "ID", "en": "ID"}, value: func(u *model.UserResponse) interface{} { return u.ID }},
+	{key: "username", headers: map[string]string{"zh": "用户名", "en": "Username"}, value: func(u *model.UserResponse) interface{} { return u.Username }},
+	{key: "email", headers: map[string]string{"zh": "邮箱", "en": "Email"}, value: func(u *model.UserResponse) interface{} { return u.Email }},
+	{key: "role", headers: map[string]string{"zh": "角色", "en": "Role"}, value: func(u *model.UserResponse) interface{} { return u.Role }},
+	{key: "status", headers: map[string]string{"zh": "状态", "en": "Status"}, value: func(u *model.UserResponse) interface{} { return u.Status }},
+	{key: "created_at", headers: map[string]string{"zh": "创建时间", "en": "Created At"}, value: func(u *model.UserResponse) interface{} { return u.CreatedAt }},
+	{key: "updated_at", headers: map[string]string{"zh": "更新时间", "en": "Updated At"}, value: func(u *model.UserResponse) interface{} { return u.UpdatedAt }},
+}
+
+// defaultExportLang 默认表头语言
+const defaultExportLang = "zh"
+
+// UserExportRequest 用户导出请求
+type UserExportRequest struct {
+	Spec    query.Spec
+	Format  export.Format
+	Columns []string // 导出的列，为空时导出全部列
+	Lang    string   // 表头语言：zh（默认）、en
+}
+
+// UserExportResult 导出方式：同步导出时 Job 为 nil，由调用方调用 Export 直接输出
+type UserExportResult struct {
+	Total int64
+	Job   *jobs.Job
+}
+
+// UserExportService 用户导出服务
+// 结果数量不超过 syncMaxRows 时直接流式输出，否则作为后台任务生成文件，完成后下载
+type UserExportService struct {
+	userRepo    UserRepositoryInterface
+	jobs        *jobs.Manager
+	syncMaxRows int64
+}
+
+func NewUserExportService(userRepo UserRepositoryInterface, jobManager *jobs.Manager, syncMaxRows int) *UserExportService {
+	return &UserExportService{
+		userRepo:    userRepo,
+		jobs:        jobManager,
+		syncMaxRows: int64(syncMaxRows),
+	}
+}
+
+// Start 校验导出请求并统计结果数量，数量超过同步上限或 async 为 true 时提交后台任务
+func (s *UserExportService) Start(ctx context.Context, owner uint, req UserExportRequest, async bool) (*UserExportResult, error) {
+	ctx, span := tracer.Start(ctx, "UserExportService.Start")
+	defer span.End()
+
+	if _, err := exportColumns(req.Columns); err != nil {
+		return nil, err
+	}
+	total, err := s.userRepo.Count(ctx, req.Spec)
+	if err != nil {
+		return nil, err
+	}
+	result := &UserExportResult{Total: total}
+	if !async && total <= s.syncMaxRows {
+		return result, nil
+	}
+
+	job, err := s.jobs.Submit(userExportJobKind, owner, ExportFileName(req.Format), req.Format.ContentType(),
+		func(ctx context.Context, w io.Writer, progress func(n int64)) error {
+			return s.Export(ctx, w, req, progress)
+		})
+	if err != nil {
+		return nil, err
+	}
+	userLog.InfoContext(ctx, "用户导出任务已提交",
+		zap.String("job_id", job.ID),
+		zap.Int64("total", total),
+		zap.String("format", string(req.Format)),
+		zap.String("operation", "export_users"))
+	result.Job = &job
+	return result, nil
+}
+
+// Export 按请求将用户逐行写入 w，progress 可为 nil
+func (s *UserExportService) Export(ctx context.Context, w io.Writer, req UserExportRequest, progress func(n int64)) error {
+	ctx, span := tracer.Start(ctx, "UserExportService.Export")
+	defer span.End()
+
+	columns, err := exportColumns(req.Columns)
+	if err != nil {
+		return err
+	}
+	ew, err := export.NewWriter(w, req.Format)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, col := range columns {
+		header[i] = col.header(req.Lang)
+	}
+	if err := ew.WriteRow(header); err != nil {
+		ew.Close()
+		return err
+	}
+
+	var n int64
+	row := make([]interface{}, len(columns))
+	err = s.userRepo.Stream(ctx, req.Spec, func(user *model.User) error {
+		resp := model.UserResponse{
+			ID:        user.ID,
+			Username:  user.Username,
+			Email:     user.Email,
+			Role:      user.Role,
+			Status:    user.Status,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		}
+		for i, col := range columns {
+			row[i] = col.value(&resp)
+		}
+		n++
+		if progress != nil && n%exportProgressInterval == 0 {
+			progress(n)
+		}
+		return ew.WriteRow(row)
+	})
+	if err != nil {
+		ew.Close()
+		userLog.ErrorContext(ctx, "导出用户失败",
+			zap.Int64("exported", n),
+			zap.Error(err),
+			zap.String("operation", "export_users"))
+		return err
+	}
+	if progress != nil {
+		progress(n)
+	}
+	if err := ew.Close(); err != nil {
+		return err
+	}
+
+	userLog.InfoContext(ctx, "导出用户成功",
+		zap.Int64("exported", n),
+		zap.String("format", string(req.Format)),
+		zap.String("operation", "export_users"))
+	return nil
+}
+
+// GetJob 获取导出任务状态，只能查看自己提交的任务
+func (s *UserExportService) GetJob(id string, owner uint) (jobs.Job, error) {
+	return s.jobs.Get(id, owner)
+}
+
+// OpenJob 打开已完成的导出结果，调用方负责关闭文件
+func (s *UserExportService) OpenJob(id string, owner uint) (jobs.Job, io.ReadSeekCloser, error) {
+	job, f, err := s.jobs.Open(id, owner)
+	if err != nil {
+		return job, nil, err
+	}
+	return job, f, nil
+}
+
+// UserExportColumnKeys 返回可导出的列名
+func UserExportColumnKeys() []string {
+	keys := make([]string, len(userExportColumns))
+	for i, col := range userExportColumns {
+		keys[i] = col.key
+	}
+	return keys
+}
+
+// ExportFileName 生成导出文件名，如 users-20240102-150405.csv
+func ExportFileName(format export.Format) string {
+	return "users-" + time.Now().Format("20060102-150405") + "." + format.Extension()
+}
+
+// exportColumns 按列名选择导出列，为空时返回全部列
+func exportColumns(keys []string) ([]userExportColumn, error) {
+	if len(keys) == 0 {
+		return userExportColumns, nil
+	}
+
+	columns := make([]userExportColumn, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		col, ok := findExportColumn(key)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown column %q, available: %s", ErrInvalidExport, key, strings.Join(UserExportColumnKeys(), ","))
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidExport, key)
+		}
+		seen[key] = true
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+func findExportColumn(key string) (userExportColumn, bool) {
+	for _, col := range userExportColumns {
+		if col.key == key {
+			return col, true
+		}
+	}
+	return userExportColumn{}, false
+}
+
+// header 返回指定语言的表头，不支持的语言使用默认语言
+func (c userExportColumn) header(lang string) string {
+	if h, ok := c.headers[lang]; ok {
+		return h
+	}
+	return c.headers[defaultExportLang]
+}
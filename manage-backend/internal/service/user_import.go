@@ -0,0 +1,471 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"regexp"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/export"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrInvalidImport 导入文件不合法（无法解析、缺少必需列、行数超限等），调用方应返回 400
+var ErrInvalidImport = errors.New("invalid import file")
+
+const (
+	// MaxImportRows 单次校验（dry-run）的最大数据行数
+	MaxImportRows = 1000
+	// MaxImportCommitRows 单次提交的最大数据行数
+	// 提交时每行都要计算 bcrypt，行数过多会超过服务器写超时
+	MaxImportCommitRows = 200
+	// importBatchSize 提交时每批插入的用户数
+	importBatchSize = 100
+	// generatedPasswordLength 生成的随机密码长度
+	generatedPasswordLength = 12
+)
+
+var (
+	// usernamePattern 用户名只能包含字母、数字、下划线和中文，与前端规则一致
+	usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_\x{4e00}-\x{9fa5}]+$`)
+
+	importRoles    = []string{"admin", "user", "moderator"}
+	importStatuses = []string{"active", "inactive", "pending"}
+)
+
+// userImportColumns 可导入的列，表头可以是列名或导出时使用的中英文表头
+var userImportColumns = []userExportColumn{
+	findUserExportColumn("username"),
+	findUserExportColumn("email"),
+	{key: "password", headers: map[string]string{"zh": "密码", "en": "Password"}},
+	findUserExportColumn("role"),
+	findUserExportColumn("status"),
+}
+
+// UserImportRequest 用户导入请求
+type UserImportRequest struct {
+	File   io.Reader
+	Format export.Format
+	// 为 false 时只校验（dry-run），为 true 时在全部行校验通过后创建用户
+	Commit bool
+	// 密码列为空时生成随机密码，生成的密码只在提交成功的报告中返回一次
+	GeneratePasswords bool
+}
+
+// UserImportRow 单行导入结果
+type UserImportRow struct {
+	Row      int      `json:"row"` // 文件中的行号，表头为第 1 行
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Role     string   `json:"role"`
+	Status   string   `json:"status"`
+	UserID   uint     `json:"user_id,omitempty"`  // 提交成功后的用户ID
+	Password string   `json:"password,omitempty"` // 提交成功后返回生成的密码
+	Errors   []string `json:"errors,omitempty"`
+
+	password  string
+	generated bool
+}
+
+// UserImportReport 导入报告
+type UserImportReport struct {
+	Total     int             `json:"total"`
+	Valid     int             `json:"valid"`
+	Invalid   int             `json:"invalid"`
+	Committed bool            `json:"committed"`
+	Created   int             `json:"created"`
+	Rows      []UserImportRow `json:"rows"`
+}
+
+// UserImportService 用户批量导入服务
+// 先逐行校验并生成报告；提交时只有全部行通过校验才会在一个事务中分批创建用户
+type UserImportService struct {
+	userRepo UserRepositoryInterface
+}
+
+func NewUserImportService(userRepo UserRepositoryInterface) *UserImportService {
+	return &UserImportService{userRepo: userRepo}
+}
+
+// Import 校验导入文件，Commit 为 true 且没有错误行时创建用户
+func (s *UserImportService) Import(ctx context.Context, req UserImportRequest) (*UserImportReport, error) {
+	ctx, span := tracer.Start(ctx, "UserImportService.Import")
+	defer span.End()
+
+	rows, err := readImportRows(req.File, req.Format)
+	if err != nil {
+		return nil, err
+	}
+	if req.Commit && len(rows) > MaxImportCommitRows {
+		return nil, fmt.Errorf("%w: at most %d rows per committed import, split the file or validate without commit", ErrInvalidImport, MaxImportCommitRows)
+	}
+	report := &UserImportReport{Total: len(rows), Rows: rows}
+	if err := s.validate(ctx, report, req.GeneratePasswords); err != nil {
+		return nil, err
+	}
+
+	userLog.InfoContext(ctx, "用户导入校验完成",
+		zap.Int("total", report.Total),
+		zap.Int("invalid", report.Invalid),
+		zap.Bool("commit", req.Commit),
+		zap.String("operation", "import_users"))
+
+	if !req.Commit || report.Invalid > 0 || report.Total == 0 {
+		return report, nil
+	}
+	if err := s.commit(ctx, report); err != nil {
+		userLog.ErrorContext(ctx, "用户导入失败",
+			zap.Int("total", report.Total),
+			zap.Error(err),
+			zap.String("operation", "import_users"))
+		return nil, err
+	}
+
+	userLog.InfoContext(ctx, "用户导入成功",
+		zap.Int("created", report.Created),
+		zap.String("operation", "import_users"))
+	return report, nil
+}
+
+// readImportRows 读取导入文件，第一行为表头，跳过空行
+func readImportRows(file io.Reader, format export.Format) ([]UserImportRow, error) {
+	r, err := export.NewReader(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	defer r.Close()
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	index, err := importColumnIndex(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []UserImportRow
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %v", ErrInvalidImport, line, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(rows) == MaxImportRows {
+			return nil, fmt.Errorf("%w: at most %d rows per import", ErrInvalidImport, MaxImportRows)
+		}
+
+		cell := func(key string) string {
+			i, ok := index[key]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		rows = append(rows, UserImportRow{
+			Row:      line,
+			Username: cell("username"),
+			Email:    cell("email"),
+			Role:     cell("role"),
+			Status:   cell("status"),
+			password: cell("password"),
+		})
+	}
+	return rows, nil
+}
+
+// importColumnIndex 根据表头确定各列位置，username 和 email 为必需列
+func importColumnIndex(header []string) (map[string]int, error) {
+	index := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" {
+			continue
+		}
+		for _, col := range userImportColumns {
+			if !col.matches(h) {
+				continue
+			}
+			if _, dup := index[col.key]; dup {
+				return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidImport, col.key)
+			}
+			index[col.key] = i
+		}
+	}
+	for _, key := range []string{"username", "email"} {
+		if _, ok := index[key]; !ok {
+			return nil, fmt.Errorf("%w: missing required column %q", ErrInvalidImport, key)
+		}
+	}
+	return index, nil
+}
+
+// validate 逐行校验字段、密码策略和重复数据，错误记录在对应行中
+// 与已有用户的重复检查对用户名和邮箱各执行一次批量查询
+func (s *UserImportService) validate(ctx context.Context, report *UserImportReport, generatePasswords bool) error {
+	usernames := make(map[string]int, len(report.Rows))
+	emails := make(map[string]int, len(report.Rows))
+	// 需要检查是否与已有用户重复的行
+	var checkUsername, checkEmail []int
+
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		if row.Role == "" {
+			row.Role = "user"
+		}
+		if row.Status == "" {
+			row.Status = "active"
+		}
+
+		usernameOK := validateImportUsername(row)
+		emailOK := validateImportEmail(row)
+		if !containsString(importRoles, row.Role) {
+			row.addError("role must be one of %s", strings.Join(importRoles, ", "))
+		}
+		if !containsString(importStatuses, row.Status) {
+			row.addError("status must be one of %s", strings.Join(importStatuses, ", "))
+		}
+		if err := validateImportPassword(row, generatePasswords); err != nil {
+			return err
+		}
+
+		// 文件内重复：邮箱不区分大小写，与已有用户比较时规则相同
+		if usernameOK {
+			if first, dup := usernames[row.Username]; dup {
+				row.addError("duplicate username in file (row %d)", first)
+				usernameOK = false
+			} else {
+				usernames[row.Username] = row.Row
+			}
+		}
+		if emailOK {
+			key := strings.ToLower(row.Email)
+			if first, dup := emails[key]; dup {
+				row.addError("duplicate email in file (row %d)", first)
+				emailOK = false
+			} else {
+				emails[key] = row.Row
+			}
+		}
+
+		if usernameOK {
+			checkUsername = append(checkUsername, i)
+		}
+		if emailOK {
+			checkEmail = append(checkEmail, i)
+		}
+	}
+
+	// 与已有用户重复
+	existingUsernames, err := existingRows(ctx, report.Rows, checkUsername, func(r *UserImportRow) string { return r.Username }, s.userRepo.FindExistingUsernames)
+	if err != nil {
+		return err
+	}
+	existingEmails, err := existingRows(ctx, report.Rows, checkEmail, func(r *UserImportRow) string { return strings.ToLower(r.Email) }, s.userRepo.FindExistingEmails)
+	if err != nil {
+		return err
+	}
+	for _, i := range existingUsernames {
+		report.Rows[i].addError("username already exists")
+	}
+	for _, i := range existingEmails {
+		report.Rows[i].addError("email already exists")
+	}
+
+	for i := range report.Rows {
+		if len(report.Rows[i].Errors) > 0 {
+			report.Invalid++
+		} else {
+			report.Valid++
+		}
+	}
+	return nil
+}
+
+// existingRows 批量查询 indexes 对应行的取值，返回其中已存在的行下标
+func existingRows(ctx context.Context, rows []UserImportRow, indexes []int,
+	value func(*UserImportRow) string, find func(context.Context, []string) ([]string, error)) ([]int, error) {
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	values := make([]string, len(indexes))
+	for j, i := range indexes {
+		values[j] = value(&rows[i])
+	}
+	found, err := find(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]struct{}, len(found))
+	for _, v := range found {
+		set[v] = struct{}{}
+	}
+
+	var result []int
+	for _, i := range indexes {
+		if _, ok := set[value(&rows[i])]; ok {
+			result = append(result, i)
+		}
+	}
+	return result, nil
+}
+
+func validateImportUsername(row *UserImportRow) bool {
+	n := utf8.RuneCountInString(row.Username)
+	switch {
+	case row.Username == "":
+		row.addError("username is required")
+	case n < 3 || n > 50:
+		row.addError("username must be 3-50 characters")
+	case !usernamePattern.MatchString(row.Username):
+		row.addError("username may only contain letters, digits, underscores and Chinese characters")
+	default:
+		return true
+	}
+	return false
+}
+
+func validateImportEmail(row *UserImportRow) bool {
+	switch {
+	case row.Email == "":
+		row.addError("email is required")
+	case len(row.Email) > 255:
+		row.addError("email must be at most 255 characters")
+	default:
+		// 只接受纯地址，不接受 "Name <addr>" 形式
+		if addr, err := mail.ParseAddress(row.Email); err != nil || addr.Address != row.Email {
+			row.addError("email is invalid")
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// validateImportPassword 校验密码策略，密码为空时按需生成
+func validateImportPassword(row *UserImportRow, generate bool) error {
+	if row.password == "" {
+		if !generate {
+			row.addError("password is required")
+			return nil
+		}
+		password, err := utils.GeneratePassword(generatedPasswordLength)
+		if err != nil {
+			return err
+		}
+		row.password = password
+		row.generated = true
+		return nil
+	}
+	if err := utils.ValidatePassword(row.password); err != nil {
+		row.addError("%s", err.Error())
+	}
+	return nil
+}
+
+// commit 加密密码并在一个事务中分批创建用户
+func (s *UserImportService) commit(ctx context.Context, report *UserImportReport) error {
+	users := make([]*model.User, len(report.Rows))
+
+	// bcrypt 计算较慢，按 CPU 数并发加密
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.NumCPU())
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			hashed, err := utils.HashPassword(row.password)
+			if err != nil {
+				return err
+			}
+			users[i] = &model.User{
+				Username: row.Username,
+				Email:    row.Email,
+				Password: hashed,
+				Role:     row.Role,
+				Status:   row.Status,
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.CreateBatch(ctx, users, importBatchSize); err != nil {
+		return err
+	}
+
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		row.UserID = users[i].ID
+		if row.generated {
+			row.Password = row.password
+		}
+	}
+	report.Committed = true
+	report.Created = len(users)
+	return nil
+}
+
+func (r *UserImportRow) addError(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// matches 判断表头是否对应该列
+func (c userExportColumn) matches(header string) bool {
+	if header == c.key {
+		return true
+	}
+	for _, h := range c.headers {
+		if header == strings.ToLower(h) {
+			return true
+		}
+	}
+	return false
+}
+
+// findUserExportColumn 按列名获取导出列定义，列名必须存在
+func findUserExportColumn(key string) userExportColumn {
+	col, ok := findExportColumn(key)
+	if !ok {
+		panic("unknown user export column: " + key)
+	}
+	return col
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
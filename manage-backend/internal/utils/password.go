@@ -1,9 +1,29 @@
 package utils
 
 import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrWeakPassword 密码不符合密码策略
+var ErrWeakPassword = errors.New("password does not meet policy")
+
+const (
+	// PasswordMinLength 密码最小长度
+	PasswordMinLength = 8
+	// PasswordMaxLength 密码最大长度（bcrypt 只使用前 72 字节，超出部分会报错）
+	PasswordMaxLength = 72
+
+	// passwordSpecialChars 特殊字符，与前端密码规则一致
+	passwordSpecialChars = "!@#$%^&*(),.?\":{}|<>"
+)
+
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(bytes), err
@@ -12,4 +32,94 @@ func HashPassword(password string) (string, error) {
 func CheckPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
-}
\ No newline at end of file
+}
+
+// ValidatePassword 校验密码策略：8-72 个字符，包含大写字母、小写字母、数字和特殊字符
+func ValidatePassword(password string) error {
+	if len(password) < PasswordMinLength {
+		return fmt.Errorf("%w: must be at least %d characters", ErrWeakPassword, PasswordMinLength)
+	}
+	if len(password) > PasswordMaxLength {
+		return fmt.Errorf("%w: must be at most %d bytes", ErrWeakPassword, PasswordMaxLength)
+	}
+
+	var lower, upper, digit, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case strings.ContainsRune(passwordSpecialChars, r):
+			special = true
+		}
+	}
+	var missing []string
+	if !lower {
+		missing = append(missing, "a lowercase letter")
+	}
+	if !upper {
+		missing = append(missing, "an uppercase letter")
+	}
+	if !digit {
+		missing = append(missing, "a digit")
+	}
+	if !special {
+		missing = append(missing, "a special character")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: must contain %s", ErrWeakPassword, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// GeneratePassword 生成符合密码策略的随机密码
+func GeneratePassword(length int) (string, error) {
+	if length < PasswordMinLength {
+		length = PasswordMinLength
+	}
+	if length > PasswordMaxLength {
+		length = PasswordMaxLength
+	}
+
+	// 每类字符至少一个，其余从全部字符中随机选取，最后打乱顺序
+	classes := []string{
+		"abcdefghijkmnopqrstuvwxyz",
+		"ABCDEFGHJKLMNPQRSTUVWXYZ",
+		"23456789",
+		"!@#$%^&*",
+	}
+	all := strings.Join(classes, "")
+
+	password := make([]byte, length)
+	for i := range password {
+		set := all
+		if i < len(classes) {
+			set = classes[i]
+		}
+		c, err := randomIndex(len(set))
+		if err != nil {
+			return "", err
+		}
+		password[i] = set[c]
+	}
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// randomIndex 返回 [0, n) 的随机数
+func randomIndex(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
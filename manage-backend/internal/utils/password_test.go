@@ -113,4 +113,46 @@ func TestPasswordHashingConsistency(t *testing.T) {
 	// But both should validate the original password
 	assert.True(t, CheckPassword(password, hash1))
 	assert.True(t, CheckPassword(password, hash2))
-}
\ No newline at end of file
+}
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{name: "valid password", password: "Passw0rd!", wantErr: false},
+		{name: "too short", password: "Pa0!", wantErr: true},
+		{name: "too long", password: "Passw0rd!" + string(make([]byte, PasswordMaxLength)), wantErr: true},
+		{name: "missing uppercase", password: "passw0rd!", wantErr: true},
+		{name: "missing lowercase", password: "PASSW0RD!", wantErr: true},
+		{name: "missing digit", password: "Password!", wantErr: true},
+		{name: "missing special character", password: "Passw0rd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePassword(tt.password)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrWeakPassword)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		password, err := GeneratePassword(12)
+		assert.NoError(t, err)
+		assert.Len(t, password, 12)
+		assert.NoError(t, ValidatePassword(password))
+		seen[password] = true
+	}
+	assert.Greater(t, len(seen), 1)
+
+	password, err := GeneratePassword(1)
+	assert.NoError(t, err)
+	assert.Len(t, password, PasswordMinLength)
+}
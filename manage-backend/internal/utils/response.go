@@ -22,6 +22,15 @@ type PaginationMeta struct {
 	TotalPages int   `json:"total_pages"`
 }
 
+// CursorPaginationMeta 游标分页元数据，不返回页码
+type CursorPaginationMeta struct {
+	PageSize       int    `json:"page_size"`
+	Total          int64  `json:"total"` // 不统计总数时为 -1
+	NextCursor     string `json:"next_cursor,omitempty"`
+	PrevCursor     string `json:"prev_cursor,omitempty"`
+	TotalEstimated bool   `json:"total_estimated,omitempty"` // total 为数据库统计信息的估算值
+}
+
 // PaginatedResponse 分页响应结构
 type PaginatedResponse struct {
 	Code       int            `json:"code"`
@@ -31,6 +40,15 @@ type PaginatedResponse struct {
 	Error      string         `json:"error,omitempty"`
 }
 
+// CursorPaginatedResponse 游标分页响应结构
+type CursorPaginatedResponse struct {
+	Code       int                  `json:"code"`
+	Message    string               `json:"message"`
+	Data       interface{}          `json:"data"`
+	Pagination CursorPaginationMeta `json:"pagination"`
+	Error      string               `json:"error,omitempty"`
+}
+
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, APIResponse{
@@ -58,6 +76,15 @@ func Created(c *gin.Context, data interface{}) {
 	})
 }
 
+// Accepted 请求已接受、将在后台处理的响应
+func Accepted(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusAccepted, APIResponse{
+		Code:    http.StatusAccepted,
+		Message: "accepted",
+		Data:    data,
+	})
+}
+
 // BadRequest 400 错误响应
 func BadRequest(c *gin.Context, message string) {
 	c.JSON(http.StatusBadRequest, APIResponse{
@@ -112,6 +139,15 @@ func InternalServerError(c *gin.Context, message string) {
 	})
 }
 
+// ServiceUnavailable 503 错误响应
+func ServiceUnavailable(c *gin.Context, message string) {
+	c.JSON(http.StatusServiceUnavailable, APIResponse{
+		Code:    http.StatusServiceUnavailable,
+		Message: "service unavailable",
+		Error:   message,
+	})
+}
+
 // PaginatedSuccess 分页成功响应
 func PaginatedSuccess(c *gin.Context, data interface{}, pagination PaginationMeta) {
 	c.JSON(http.StatusOK, PaginatedResponse{
@@ -122,6 +158,16 @@ func PaginatedSuccess(c *gin.Context, data interface{}, pagination PaginationMet
 	})
 }
 
+// CursorPaginatedSuccess 游标分页成功响应
+func CursorPaginatedSuccess(c *gin.Context, data interface{}, pagination CursorPaginationMeta) {
+	c.JSON(http.StatusOK, CursorPaginatedResponse{
+		Code:       http.StatusOK,
+		Message:    "success",
+		Data:       data,
+		Pagination: pagination,
+	})
+}
+
 // ValidationError 参数验证错误响应
 func ValidationError(c *gin.Context, err error) {
 	c.JSON(http.StatusBadRequest, APIResponse{
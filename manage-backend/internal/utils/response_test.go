@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginatedSuccessKeepsPageFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	// 没有数据时 page 和 total_pages 仍然返回
+	PaginatedSuccess(c, []string{}, PaginationMeta{Page: 1, PageSize: 10})
+
+	var body struct {
+		Pagination map[string]interface{} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{
+		"page":        float64(1),
+		"page_size":   float64(10),
+		"total":       float64(0),
+		"total_pages": float64(0),
+	}, body.Pagination)
+}
+
+func TestCursorPaginatedSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	CursorPaginatedSuccess(c, []string{}, CursorPaginationMeta{PageSize: 10, Total: -1, NextCursor: "abc"})
+
+	var body struct {
+		Pagination map[string]interface{} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{
+		"page_size":   float64(10),
+		"total":       float64(-1),
+		"next_cursor": "abc",
+	}, body.Pagination)
+}
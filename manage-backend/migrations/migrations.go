@@ -0,0 +1,15 @@
+// Package migrations 内嵌版本化 SQL 迁移文件
+//
+// 文件命名格式为 NNNNNN_name.up.sql / NNNNNN_name.down.sql，
+// 数字前缀为版本号，迁移按版本号顺序执行，down 文件可省略（表示不可回滚）。
+//
+// 通用文件按 PostgreSQL 语法编写；MySQL 和 SQLite 语法不同时添加方言专用文件，
+// 如 NNNNNN_name.up.mysql.sql、NNNNNN_name.up.sqlite.sql，专用文件优先于通用文件。
+package migrations
+
+import "embed"
+
+// FS 内嵌的 SQL 迁移文件
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrCircuitOpen 熔断器处于打开状态，请求被直接拒绝
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState 熔断器状态
+type BreakerState int32
+
+const (
+	StateClosed   BreakerState = iota // 关闭：正常放行
+	StateOpen                         // 打开：直接拒绝
+	StateHalfOpen                     // 半开：放行一个探测请求
+)
+
+// String 返回状态名称
+func (s BreakerState) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerStats 熔断器运行指标
+type BreakerStats struct {
+	Name                string `json:"name"`
+	State               string `json:"state"`
+	Requests            uint64 `json:"requests"`             // 实际执行的请求数
+	Failures            uint64 `json:"failures"`             // 失败的请求数
+	Rejected            uint64 `json:"rejected"`             // 熔断期间被拒绝的请求数
+	Trips               uint64 `json:"trips"`                // 熔断器打开的次数
+	ConsecutiveFailures int    `json:"consecutive_failures"` // 当前连续失败次数
+}
+
+// CircuitBreaker 简单的连续失败计数熔断器
+// - 连续失败达到 threshold 次后打开，期间所有请求返回 ErrCircuitOpen
+// - 打开 cooldown 时间后进入半开状态，放行一个探测请求
+// - 探测成功则关闭，失败则重新打开
+type CircuitBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu                  sync.Mutex
+	state               BreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+
+	requests atomic.Uint64
+	failures atomic.Uint64
+	rejected atomic.Uint64
+	trips    atomic.Uint64
+}
+
+// NewCircuitBreaker 创建熔断器
+// name: 熔断器名称（用于日志和指标）
+// threshold: 连续失败多少次后熔断
+// cooldown: 熔断后多久进入半开状态
+func NewCircuitBreaker(name string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 10 * time.Second
+	}
+	return &CircuitBreaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Execute 通过熔断器执行 fn
+// 熔断期间不会调用 fn，直接返回 ErrCircuitOpen
+func (b *CircuitBreaker) Execute(fn func() error) error {
+	if !b.allow() {
+		b.rejected.Add(1)
+		return ErrCircuitOpen
+	}
+
+	b.requests.Add(1)
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// State 返回当前状态
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState()
+}
+
+// Stats 返回熔断器指标快照
+func (b *CircuitBreaker) Stats() BreakerStats {
+	b.mu.Lock()
+	state := b.currentState()
+	consecutive := b.consecutiveFailures
+	b.mu.Unlock()
+
+	return BreakerStats{
+		Name:                b.name,
+		State:               state.String(),
+		Requests:            b.requests.Load(),
+		Failures:            b.failures.Load(),
+		Rejected:            b.rejected.Load(),
+		Trips:               b.trips.Load(),
+		ConsecutiveFailures: consecutive,
+	}
+}
+
+// currentState 计算当前状态（调用方需持有锁）
+// 打开状态超过冷却时间后视为半开
+func (b *CircuitBreaker) currentState() BreakerState {
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cooldown {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// allow 判断本次请求是否放行
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case StateClosed:
+		return true
+	case StateHalfOpen:
+		// 半开状态只放行一个探测请求
+		if b.probing {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return true
+	default:
+		return false
+	}
+}
+
+// record 根据执行结果更新熔断器状态
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		if b.state != StateClosed {
+			logger.Info("熔断器已恢复", zap.String("breaker", b.name))
+		}
+		b.state = StateClosed
+		b.consecutiveFailures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures.Add(1)
+	b.consecutiveFailures++
+
+	if b.state == StateHalfOpen || b.consecutiveFailures >= b.threshold {
+		if b.state != StateOpen {
+			b.trips.Add(1)
+			logger.Warn("熔断器已打开",
+				zap.String("breaker", b.name),
+				zap.Int("consecutive_failures", b.consecutiveFailures),
+				zap.Duration("cooldown", b.cooldown),
+				zap.Error(err))
+		}
+		b.state = StateOpen
+		b.openedAt = time.Now()
+		b.probing = false
+	}
+}
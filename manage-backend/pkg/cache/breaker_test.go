@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	logger.Init("error")
+	errRedis := errors.New("redis down")
+
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		b := NewCircuitBreaker("test", 3, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, errRedis, b.Execute(func() error { return errRedis }))
+		}
+		assert.Equal(t, StateOpen, b.State())
+
+		called := false
+		err := b.Execute(func() error { called = true; return nil })
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.False(t, called)
+
+		stats := b.Stats()
+		assert.Equal(t, uint64(3), stats.Requests)
+		assert.Equal(t, uint64(3), stats.Failures)
+		assert.Equal(t, uint64(1), stats.Rejected)
+		assert.Equal(t, uint64(1), stats.Trips)
+	})
+
+	t.Run("success resets failure count", func(t *testing.T) {
+		b := NewCircuitBreaker("test", 3, time.Minute)
+
+		b.Execute(func() error { return errRedis })
+		b.Execute(func() error { return errRedis })
+		b.Execute(func() error { return nil })
+		b.Execute(func() error { return errRedis })
+
+		assert.Equal(t, StateClosed, b.State())
+		assert.Equal(t, 1, b.Stats().ConsecutiveFailures)
+	})
+
+	t.Run("half-open probe closes on success", func(t *testing.T) {
+		b := NewCircuitBreaker("test", 1, 20*time.Millisecond)
+
+		b.Execute(func() error { return errRedis })
+		assert.Equal(t, StateOpen, b.State())
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, StateHalfOpen, b.State())
+
+		assert.NoError(t, b.Execute(func() error { return nil }))
+		assert.Equal(t, StateClosed, b.State())
+	})
+
+	t.Run("half-open probe reopens on failure", func(t *testing.T) {
+		b := NewCircuitBreaker("test", 1, 20*time.Millisecond)
+
+		b.Execute(func() error { return errRedis })
+		time.Sleep(30 * time.Millisecond)
+
+		assert.Equal(t, errRedis, b.Execute(func() error { return errRedis }))
+		assert.Equal(t, StateOpen, b.State())
+		assert.Equal(t, uint64(2), b.Stats().Trips)
+	})
+}
@@ -0,0 +1,382 @@
+package cache
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrWrongType 对键执行了与其类型不符的操作（与 Redis WRONGTYPE 错误一致）
+var ErrWrongType = errors.New("cache: operation against a key holding the wrong kind of value")
+
+// memoryItem 内存存储中的一个键
+// value 和 set 二选一：字符串键使用 value，集合键使用 set
+type memoryItem struct {
+	value     string
+	set       map[string]struct{}
+	expiresAt time.Time // 零值表示永不过期
+}
+
+func (i *memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && now.After(i.expiresAt)
+}
+
+// MemoryStore 进程内缓存存储
+// 协程安全，支持 TTL 过期：读取时惰性删除过期键，并由后台协程定期清理
+// 适用于单节点部署和单元测试，多实例部署时各实例数据互不共享
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[string]*memoryItem
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// 确保 MemoryStore 实现了 Store 接口
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore 创建内存存储
+// cleanupInterval: 后台清理过期键的间隔，<= 0 时使用 1 分钟
+func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
+	if cleanupInterval <= 0 {
+		cleanupInterval = time.Minute
+	}
+
+	s := &MemoryStore{
+		items: make(map[string]*memoryItem),
+		stop:  make(chan struct{}),
+	}
+	go s.janitor(cleanupInterval)
+	return s
+}
+
+// janitor 定期清理过期键
+func (s *MemoryStore) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.deleteExpired()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// deleteExpired 删除所有过期键
+func (s *MemoryStore) deleteExpired() {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, item := range s.items {
+		if item.expired(now) {
+			delete(s.items, key)
+		}
+	}
+}
+
+// getLocked 获取未过期的键（调用方需持有锁）
+func (s *MemoryStore) getLocked(key string) (*memoryItem, bool) {
+	item, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	if item.expired(time.Now()) {
+		delete(s.items, key)
+		return nil, false
+	}
+	return item, true
+}
+
+// expiresAt 根据过期时长计算过期时间
+func expiresAt(expiration time.Duration) time.Time {
+	if expiration <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(expiration)
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.getLocked(key)
+	if !ok {
+		return "", ErrNotFound
+	}
+	if item.set != nil {
+		return "", ErrWrongType
+	}
+	return item.value, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	str, err := toString(value)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[key] = &memoryItem{value: str, expiresAt: expiresAt(expiration)}
+	return nil
+}
+
+func (s *MemoryStore) Del(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.items, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Exists(ctx context.Context, keys ...string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	for _, key := range keys {
+		if _, ok := s.getLocked(key); ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *MemoryStore) ExistsEach(ctx context.Context, keys ...string) ([]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists := make([]bool, len(keys))
+	for i, key := range keys {
+		_, exists[i] = s.getLocked(key)
+	}
+	return exists, nil
+}
+
+func (s *MemoryStore) Incr(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.getLocked(key)
+	if !ok {
+		s.items[key] = &memoryItem{value: "1"}
+		return 1, nil
+	}
+	if item.set != nil {
+		return 0, ErrWrongType
+	}
+
+	n, err := strconv.ParseInt(item.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cache: value is not an integer: %w", err)
+	}
+	n++
+	item.value = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+func (s *MemoryStore) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.getLocked(key)
+	if !ok {
+		return false, nil
+	}
+	// 与 Redis 一致：非正数过期时间会立即删除键
+	if expiration <= 0 {
+		delete(s.items, key)
+		return true, nil
+	}
+	item.expiresAt = time.Now().Add(expiration)
+	return true, nil
+}
+
+func (s *MemoryStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	str, err := toString(value)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.getLocked(key); ok {
+		return false, nil
+	}
+	s.items[key] = &memoryItem{value: str, expiresAt: expiresAt(expiration)}
+	return true, nil
+}
+
+// getSetLocked 获取集合键，create 为 true 时不存在则创建（调用方需持有锁）
+func (s *MemoryStore) getSetLocked(key string, create bool) (*memoryItem, error) {
+	item, ok := s.getLocked(key)
+	if !ok {
+		if !create {
+			return nil, nil
+		}
+		item = &memoryItem{set: make(map[string]struct{})}
+		s.items[key] = item
+		return item, nil
+	}
+	if item.set == nil {
+		return nil, ErrWrongType
+	}
+	return item, nil
+}
+
+func (s *MemoryStore) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.getSetLocked(key, true)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		str, err := toString(member)
+		if err != nil {
+			return err
+		}
+		item.set[str] = struct{}{}
+	}
+	return nil
+}
+
+func (s *MemoryStore) SRem(ctx context.Context, key string, members ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.getSetLocked(key, false)
+	if err != nil || item == nil {
+		return err
+	}
+	for _, member := range members {
+		str, err := toString(member)
+		if err != nil {
+			return err
+		}
+		delete(item.set, str)
+	}
+	// 与 Redis 一致：空集合自动删除
+	if len(item.set) == 0 {
+		delete(s.items, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.getSetLocked(key, false)
+	if err != nil || item == nil {
+		return []string{}, err
+	}
+	members := make([]string, 0, len(item.set))
+	for member := range item.set {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (s *MemoryStore) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+	str, err := toString(member)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.getSetLocked(key, false)
+	if err != nil || item == nil {
+		return false, err
+	}
+	_, ok := item.set[str]
+	return ok, nil
+}
+
+func (s *MemoryStore) SCard(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.getSetLocked(key, false)
+	if err != nil || item == nil {
+		return 0, err
+	}
+	return int64(len(item.set)), nil
+}
+
+// Close 停止后台清理协程
+func (s *MemoryStore) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	return nil
+}
+
+// toString 按 go-redis 的参数编码规则将值转换为字符串
+func toString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.FormatInt(v.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		b, err := v.MarshalBinary()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("cache: can't marshal %T (implement encoding.BinaryMarshaler)", value)
+	}
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Get Set Del", func(t *testing.T) {
+		s := NewMemoryStore(time.Minute)
+		defer s.Close()
+
+		_, err := s.Get(ctx, "missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		require.NoError(t, s.Set(ctx, "k", []byte("v"), 0))
+		val, err := s.Get(ctx, "k")
+		require.NoError(t, err)
+		assert.Equal(t, "v", val)
+
+		require.NoError(t, s.Set(ctx, "n", 42, 0))
+		val, _ = s.Get(ctx, "n")
+		assert.Equal(t, "42", val)
+
+		count, err := s.Exists(ctx, "k", "n", "missing")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		exists, err := s.ExistsEach(ctx, "k", "missing", "n")
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false, true}, exists)
+
+		require.NoError(t, s.Del(ctx, "k", "n"))
+		count, _ = s.Exists(ctx, "k", "n")
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("TTL expiration", func(t *testing.T) {
+		s := NewMemoryStore(10 * time.Millisecond)
+		defer s.Close()
+
+		require.NoError(t, s.Set(ctx, "short", "v", 20*time.Millisecond))
+		require.NoError(t, s.Set(ctx, "long", "v", 0))
+
+		time.Sleep(50 * time.Millisecond)
+
+		_, err := s.Get(ctx, "short")
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = s.Get(ctx, "long")
+		assert.NoError(t, err)
+
+		// 后台协程已清理过期键
+		s.mu.Lock()
+		_, stillThere := s.items["short"]
+		s.mu.Unlock()
+		assert.False(t, stillThere)
+	})
+
+	t.Run("Incr Expire SetNX", func(t *testing.T) {
+		s := NewMemoryStore(time.Minute)
+		defer s.Close()
+
+		n, err := s.Incr(ctx, "counter")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		n, _ = s.Incr(ctx, "counter")
+		assert.Equal(t, int64(2), n)
+
+		require.NoError(t, s.Set(ctx, "text", "abc", 0))
+		_, err = s.Incr(ctx, "text")
+		assert.Error(t, err)
+
+		ok, err := s.Expire(ctx, "missing", time.Second)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		ok, _ = s.Expire(ctx, "counter", 10*time.Millisecond)
+		assert.True(t, ok)
+		time.Sleep(20 * time.Millisecond)
+		_, err = s.Get(ctx, "counter")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		ok, err = s.SetNX(ctx, "lock", "a", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, _ = s.SetNX(ctx, "lock", "b", time.Minute)
+		assert.False(t, ok)
+		val, _ := s.Get(ctx, "lock")
+		assert.Equal(t, "a", val)
+	})
+
+	t.Run("Sets", func(t *testing.T) {
+		s := NewMemoryStore(time.Minute)
+		defer s.Close()
+
+		require.NoError(t, s.SAdd(ctx, "set", 1, 2, "3", 2))
+		card, err := s.SCard(ctx, "set")
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), card)
+
+		members, err := s.SMembers(ctx, "set")
+		require.NoError(t, err)
+		sort.Strings(members)
+		assert.Equal(t, []string{"1", "2", "3"}, members)
+
+		ok, _ := s.SIsMember(ctx, "set", 2)
+		assert.True(t, ok)
+
+		require.NoError(t, s.SRem(ctx, "set", 1, 2, 3))
+		count, _ := s.Exists(ctx, "set")
+		assert.Equal(t, int64(0), count)
+
+		require.NoError(t, s.Set(ctx, "str", "v", 0))
+		assert.ErrorIs(t, s.SAdd(ctx, "str", "x"), ErrWrongType)
+		_, err = s.Get(ctx, "set")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Concurrent Incr", func(t *testing.T) {
+		s := NewMemoryStore(time.Minute)
+		defer s.Close()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.Incr(ctx, "counter")
+			}()
+		}
+		wg.Wait()
+
+		val, _ := s.Get(ctx, "counter")
+		assert.Equal(t, "50", val)
+	})
+}
@@ -2,41 +2,294 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
 )
 
+// Redis 部署模式
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
+// RedisClient 基于 go-redis 的 Store 实现
+// 支持单节点、哨兵和集群模式，所有键自动加上配置的前缀
 type RedisClient struct {
-	client *redis.Client
+	client redis.UniversalClient
+	prefix string
 }
 
-func NewRedisClient(cfg config.Redis) *RedisClient {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
-	})
+// 确保 RedisClient 实现了 Store 接口
+var _ Store = (*RedisClient)(nil)
+
+// NewRedisClient 根据配置创建 Redis 客户端
+func NewRedisClient(cfg config.Redis) (*RedisClient, error) {
+	opts, err := buildUniversalOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var rdb redis.UniversalClient
+	switch cfg.Mode {
+	case RedisModeSentinel:
+		if cfg.MasterName == "" {
+			return nil, errors.New("redis sentinel mode requires master_name")
+		}
+		rdb = redis.NewFailoverClient(opts.Failover())
+	case RedisModeCluster:
+		rdb = redis.NewClusterClient(opts.Cluster())
+	case RedisModeStandalone, "":
+		rdb = redis.NewClient(opts.Simple())
+	default:
+		return nil, fmt.Errorf("unknown redis mode: %s", cfg.Mode)
+	}
+
+	return &RedisClient{client: rdb, prefix: normalizePrefix(cfg.KeyPrefix)}, nil
+}
 
-	return &RedisClient{client: rdb}
+// NewRedisStore 使用已有的 Redis 客户端创建 Store（不带键前缀）
+func NewRedisStore(client redis.UniversalClient) *RedisClient {
+	return &RedisClient{client: client}
+}
+
+// buildUniversalOptions 将配置转换为 go-redis 通用选项
+func buildUniversalOptions(cfg config.Redis) (*redis.UniversalOptions, error) {
+	addrs := cfg.Addrs
+	if len(addrs) == 0 || cfg.Mode == RedisModeStandalone || cfg.Mode == "" {
+		// 单节点模式优先使用 host/port，兼容旧配置
+		if cfg.Host != "" {
+			addrs = []string{fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)}
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, errors.New("redis address is not configured")
+	}
+
+	tlsConfig, err := buildTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redis.UniversalOptions{
+		Addrs:            addrs,
+		DB:               cfg.DB,
+		Username:         cfg.Username,
+		Password:         cfg.Password,
+		MasterName:       cfg.MasterName,
+		SentinelUsername: cfg.SentinelUsername,
+		SentinelPassword: cfg.SentinelPassword,
+		TLSConfig:        tlsConfig,
+		PoolSize:         cfg.PoolSize,
+		MinIdleConns:     cfg.MinIdleConns,
+		MaxRetries:       cfg.MaxRetries,
+		DialTimeout:      cfg.DialTimeout,
+		ReadTimeout:      cfg.ReadTimeout,
+		WriteTimeout:     cfg.WriteTimeout,
+		PoolTimeout:      cfg.PoolTimeout,
+		ConnMaxIdleTime:  cfg.ConnMaxIdleTime,
+	}, nil
+}
+
+// buildTLSConfig 根据配置构建 TLS 设置，未启用时返回 nil
+func buildTLSConfig(cfg config.RedisTLS) (*tls.Config, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CAFile != "" {
+		caCert, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redis CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse redis CA file: %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load redis client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// normalizePrefix 规范化键前缀，非空时确保以 ":" 结尾
+func normalizePrefix(prefix string) string {
+	if prefix == "" || strings.HasSuffix(prefix, ":") {
+		return prefix
+	}
+	return prefix + ":"
+}
+
+// key 为键加上前缀
+func (r *RedisClient) key(key string) string {
+	return r.prefix + key
+}
+
+// keys 为多个键加上前缀
+func (r *RedisClient) keys(keys []string) []string {
+	if r.prefix == "" {
+		return keys
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = r.prefix + key
+	}
+	return prefixed
 }
 
 func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	return r.client.Set(ctx, key, value, expiration).Err()
+	return r.client.Set(ctx, r.key(key), value, expiration).Err()
 }
 
+// Get 获取值，键不存在时返回 ErrNotFound
 func (r *RedisClient) Get(ctx context.Context, key string) (string, error) {
-	return r.client.Get(ctx, key).Result()
+	val, err := r.client.Get(ctx, r.key(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+	return val, err
 }
 
+// Del 删除键
+// 集群模式下多键命令要求所有键在同一个哈希槽，否则返回 CROSSSLOT，因此逐个删除（通过 Pipeline 按节点批量发送）
 func (r *RedisClient) Del(ctx context.Context, keys ...string) error {
-	return r.client.Del(ctx, keys...).Err()
+	if !r.isCluster() || len(keys) <= 1 {
+		return r.client.Del(ctx, r.keys(keys)...).Err()
+	}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range r.keys(keys) {
+			pipe.Del(ctx, key)
+		}
+		return nil
+	})
+	return err
 }
 
+// Exists 返回存在的键数量，集群模式下逐个检查（原因同 Del）
 func (r *RedisClient) Exists(ctx context.Context, keys ...string) (int64, error) {
-	return r.client.Exists(ctx, keys...).Result()
+	if !r.isCluster() || len(keys) <= 1 {
+		return r.client.Exists(ctx, r.keys(keys)...).Result()
+	}
+	cmds := make([]*redis.IntCmd, 0, len(keys))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range r.keys(keys) {
+			cmds = append(cmds, pipe.Exists(ctx, key))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, cmd := range cmds {
+		n += cmd.Val()
+	}
+	return n, nil
+}
+
+func (r *RedisClient) ExistsEach(ctx context.Context, keys ...string) ([]bool, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	cmds := make([]*redis.IntCmd, 0, len(keys))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range r.keys(keys) {
+			cmds = append(cmds, pipe.Exists(ctx, key))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	exists := make([]bool, len(cmds))
+	for i, cmd := range cmds {
+		exists[i] = cmd.Val() > 0
+	}
+	return exists, nil
+}
+
+// isCluster 是否为集群模式的客户端
+func (r *RedisClient) isCluster() bool {
+	_, ok := r.client.(*redis.ClusterClient)
+	return ok
+}
+
+func (r *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, r.key(key)).Result()
+}
+
+func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	return r.client.Expire(ctx, r.key(key), expiration).Result()
+}
+
+func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, r.key(key), value, expiration).Result()
+}
+
+func (r *RedisClient) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	return r.client.SAdd(ctx, r.key(key), members...).Err()
+}
+
+func (r *RedisClient) SRem(ctx context.Context, key string, members ...interface{}) error {
+	return r.client.SRem(ctx, r.key(key), members...).Err()
+}
+
+func (r *RedisClient) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, r.key(key)).Result()
+}
+
+func (r *RedisClient) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+	return r.client.SIsMember(ctx, r.key(key), member).Result()
+}
+
+func (r *RedisClient) SCard(ctx context.Context, key string) (int64, error) {
+	return r.client.SCard(ctx, r.key(key)).Result()
+}
+
+// Ping 检查 Redis 连接
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// ServerVersion 返回 Redis 服务端版本（集群模式下为任一节点的版本）
+func (r *RedisClient) ServerVersion(ctx context.Context) (string, error) {
+	info, err := r.client.Info(ctx, "server").Result()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(info, "\r\n") {
+		if version, ok := strings.CutPrefix(line, "redis_version:"); ok {
+			return version, nil
+		}
+	}
+	return "", nil
+}
+
+// Prefix 返回键前缀
+func (r *RedisClient) Prefix() string {
+	return r.prefix
 }
 
 func (r *RedisClient) Close() error {
@@ -44,6 +297,7 @@ func (r *RedisClient) Close() error {
 }
 
 // GetClient 返回底层的 Redis 客户端
-func (r *RedisClient) GetClient() *redis.Client {
+// 注意：直接使用底层客户端时不会自动加键前缀
+func (r *RedisClient) GetClient() redis.UniversalClient {
 	return r.client
-}
\ No newline at end of file
+}
@@ -0,0 +1,255 @@
+package cache
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildUniversalOptions(t *testing.T) {
+	t.Run("standalone prefers host and port", func(t *testing.T) {
+		opts, err := buildUniversalOptions(config.Redis{Host: "redis", Port: "6380", Addrs: []string{"other:6379"}, DB: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"redis:6380"}, opts.Addrs)
+		assert.Equal(t, 2, opts.DB)
+		assert.Nil(t, opts.TLSConfig)
+	})
+
+	t.Run("standalone falls back to addrs", func(t *testing.T) {
+		opts, err := buildUniversalOptions(config.Redis{Mode: RedisModeStandalone, Addrs: []string{"redis:6379"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"redis:6379"}, opts.Addrs)
+	})
+
+	t.Run("cluster and sentinel use addrs", func(t *testing.T) {
+		for _, mode := range []string{RedisModeCluster, RedisModeSentinel} {
+			opts, err := buildUniversalOptions(config.Redis{
+				Mode:       mode,
+				Host:       "ignored",
+				Port:       "6379",
+				Addrs:      []string{"a:7000", "b:7001"},
+				MasterName: "mymaster",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a:7000", "b:7001"}, opts.Addrs, mode)
+			assert.Equal(t, "mymaster", opts.MasterName)
+		}
+	})
+
+	t.Run("missing address", func(t *testing.T) {
+		_, err := buildUniversalOptions(config.Redis{Mode: RedisModeCluster})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewRedisClientModes(t *testing.T) {
+	newClient := func(cfg config.Redis) *RedisClient {
+		t.Helper()
+		client, err := NewRedisClient(cfg)
+		require.NoError(t, err)
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+
+	standalone := newClient(config.Redis{Host: "localhost", Port: "6379", KeyPrefix: "app"})
+	assert.IsType(t, &redis.Client{}, standalone.GetClient())
+	assert.Equal(t, "app:", standalone.Prefix())
+
+	cluster := newClient(config.Redis{Mode: RedisModeCluster, Addrs: []string{"localhost:7000"}})
+	assert.IsType(t, &redis.ClusterClient{}, cluster.GetClient())
+
+	sentinel := newClient(config.Redis{Mode: RedisModeSentinel, Addrs: []string{"localhost:26379"}, MasterName: "mymaster"})
+	assert.IsType(t, &redis.Client{}, sentinel.GetClient())
+
+	_, err := NewRedisClient(config.Redis{Mode: RedisModeSentinel, Addrs: []string{"localhost:26379"}})
+	assert.ErrorContains(t, err, "master_name")
+
+	_, err = NewRedisClient(config.Redis{Mode: "replicated", Addrs: []string{"localhost:6379"}})
+	assert.ErrorContains(t, err, "unknown redis mode")
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	tlsConfig, err := buildTLSConfig(config.RedisTLS{CAFile: "ignored"})
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig, "disabled TLS")
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCert(t, dir)
+
+	tlsConfig, err = buildTLSConfig(config.RedisTLS{
+		Enabled:    true,
+		CAFile:     certFile,
+		CertFile:   certFile,
+		KeyFile:    keyFile,
+		ServerName: "redis.internal",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	assert.Equal(t, "redis.internal", tlsConfig.ServerName)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Len(t, tlsConfig.Certificates, 1)
+
+	_, err = buildTLSConfig(config.RedisTLS{Enabled: true, CAFile: filepath.Join(dir, "missing.pem")})
+	assert.ErrorContains(t, err, "CA file")
+
+	invalid := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a certificate"), 0o600))
+	_, err = buildTLSConfig(config.RedisTLS{Enabled: true, CAFile: invalid})
+	assert.ErrorContains(t, err, "failed to parse")
+
+	_, err = buildTLSConfig(config.RedisTLS{Enabled: true, CertFile: certFile})
+	assert.ErrorContains(t, err, "client certificate")
+}
+
+func TestRedisKeyPrefix(t *testing.T) {
+	r := &RedisClient{prefix: normalizePrefix("app")}
+	assert.Equal(t, "app:user:1", r.key("user:1"))
+	assert.Equal(t, []string{"app:a", "app:b"}, r.keys([]string{"a", "b"}))
+
+	r = &RedisClient{prefix: normalizePrefix("app:")}
+	assert.Equal(t, "app:user:1", r.key("user:1"))
+
+	r = &RedisClient{}
+	assert.Equal(t, "user:1", r.key("user:1"))
+	assert.Equal(t, []string{"a", "b"}, r.keys([]string{"a", "b"}))
+}
+
+// recordHook 记录发送的命令而不访问网络，EXISTS 等整数命令返回 1
+type recordHook struct {
+	mu        sync.Mutex
+	cmds      [][]interface{}
+	pipelines int
+}
+
+func (h *recordHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *recordHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.record(cmd)
+		return nil
+	}
+}
+
+func (h *recordHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		h.mu.Lock()
+		h.pipelines++
+		h.mu.Unlock()
+		for _, cmd := range cmds {
+			h.record(cmd)
+		}
+		return nil
+	}
+}
+
+func (h *recordHook) record(cmd redis.Cmder) {
+	if c, ok := cmd.(*redis.IntCmd); ok {
+		c.SetVal(1)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cmds = append(h.cmds, cmd.Args())
+}
+
+func TestRedisMultiKeyCommandsInClusterMode(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cluster sends one key per command", func(t *testing.T) {
+		client := redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{"localhost:7000"}})
+		defer client.Close()
+		hook := &recordHook{}
+		client.AddHook(hook)
+		r := &RedisClient{client: client, prefix: "app:"}
+
+		require.NoError(t, r.Del(ctx, "user:id:1", "user:name:alice"))
+		n, err := r.Exists(ctx, "user:id:1", "user:name:alice")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		assert.Equal(t, 2, hook.pipelines)
+		assert.Equal(t, [][]interface{}{
+			{"del", "app:user:id:1"},
+			{"del", "app:user:name:alice"},
+			{"exists", "app:user:id:1"},
+			{"exists", "app:user:name:alice"},
+		}, hook.cmds)
+	})
+
+	t.Run("standalone sends a single command", func(t *testing.T) {
+		client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+		defer client.Close()
+		hook := &recordHook{}
+		client.AddHook(hook)
+		r := &RedisClient{client: client}
+
+		require.NoError(t, r.Del(ctx, "a", "b"))
+		assert.Zero(t, hook.pipelines)
+		assert.Equal(t, [][]interface{}{{"del", "a", "b"}}, hook.cmds)
+	})
+}
+
+func TestRedisExistsEachUsesOnePipeline(t *testing.T) {
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+	hook := &recordHook{}
+	client.AddHook(hook)
+	r := &RedisClient{client: client, prefix: "app:"}
+
+	exists, err := r.ExistsEach(ctx, "user:session:1", "user:session:2")
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true}, exists)
+	assert.Equal(t, 1, hook.pipelines)
+	assert.Equal(t, [][]interface{}{
+		{"exists", "app:user:session:1"},
+		{"exists", "app:user:session:2"},
+	}, hook.cmds)
+
+	exists, err = r.ExistsEach(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, exists)
+	assert.Equal(t, 1, hook.pipelines)
+}
+
+// writeTestCert 生成自签名证书和私钥，证书同时用作 CA
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis.internal"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
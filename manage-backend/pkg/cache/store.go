@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+)
+
+// ErrNotFound 键不存在
+var ErrNotFound = errors.New("cache: key not found")
+
+// 缓存驱动类型
+const (
+	DriverRedis  = "redis"
+	DriverMemory = "memory"
+)
+
+// Store 缓存存储接口
+// 语义与 Redis 命令保持一致，业务代码只依赖该接口，便于在 Redis 和内存实现之间切换
+type Store interface {
+	// Get 获取字符串值，键不存在时返回 ErrNotFound
+	Get(ctx context.Context, key string) (string, error)
+	// Set 设置值，expiration 为 0 表示永不过期
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	// Del 删除键
+	Del(ctx context.Context, keys ...string) error
+	// Exists 返回存在的键数量
+	Exists(ctx context.Context, keys ...string) (int64, error)
+	// ExistsEach 按顺序返回每个键是否存在，Redis 实现通过一次 pipeline 完成
+	ExistsEach(ctx context.Context, keys ...string) ([]bool, error)
+	// Incr 将整数值加一，键不存在时从 0 开始
+	Incr(ctx context.Context, key string) (int64, error)
+	// Expire 设置过期时间，键不存在时返回 false
+	Expire(ctx context.Context, key string, expiration time.Duration) (bool, error)
+	// SetNX 仅当键不存在时设置，返回是否设置成功
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+
+	// SAdd 向集合添加成员
+	SAdd(ctx context.Context, key string, members ...interface{}) error
+	// SRem 从集合移除成员
+	SRem(ctx context.Context, key string, members ...interface{}) error
+	// SMembers 返回集合所有成员
+	SMembers(ctx context.Context, key string) ([]string, error)
+	// SIsMember 判断是否为集合成员
+	SIsMember(ctx context.Context, key string, member interface{}) (bool, error)
+	// SCard 返回集合成员数量
+	SCard(ctx context.Context, key string) (int64, error)
+
+	// Close 释放资源
+	Close() error
+}
+
+// NewStore 根据配置创建缓存存储
+// driver 为 memory 时使用进程内存储（单节点部署或测试），为 redis 或空时使用 Redis
+func NewStore(cfg config.CacheConfig, redisCfg config.Redis) (Store, error) {
+	switch cfg.Driver {
+	case DriverMemory:
+		return NewMemoryStore(cfg.CleanupInterval), nil
+	case DriverRedis, "":
+		return NewRedisClient(redisCfg)
+	default:
+		return nil, fmt.Errorf("unsupported cache driver: %q", cfg.Driver)
+	}
+}
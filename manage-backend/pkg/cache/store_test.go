@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStore(t *testing.T) {
+	store, err := NewStore(config.CacheConfig{Driver: DriverMemory, CleanupInterval: time.Minute}, config.Redis{})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStore{}, store)
+	store.Close()
+
+	for _, driver := range []string{DriverRedis, ""} {
+		store, err := NewStore(config.CacheConfig{Driver: driver}, config.Redis{Host: "localhost", Port: "6379"})
+		require.NoError(t, err)
+		assert.IsType(t, &RedisClient{}, store, "driver %q", driver)
+		store.Close()
+	}
+
+	_, err = NewStore(config.CacheConfig{Driver: "memroy"}, config.Redis{})
+	assert.ErrorContains(t, err, "unsupported cache driver")
+}
@@ -0,0 +1,406 @@
+package database
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// BackupFormatVersion 备份归档格式版本，格式不兼容时递增
+const BackupFormatVersion = 1
+
+// backupManifestName 归档中清单文件的名称，始终是归档的第一个文件
+const backupManifestName = "manifest.json"
+
+// backupBatchSize 导出和恢复时每批处理的行数
+const backupBatchSize = 500
+
+// ErrDatabaseNotEmpty 恢复的目标表中已有数据
+var ErrDatabaseNotEmpty = errors.New("target database is not empty")
+
+// backupModels 参与逻辑备份的模型，按恢复顺序排列
+// 新增业务表时在这里登记；迁移记录不导出，由清单中的 schema_version 表示
+func backupModels() []interface{} {
+	return []interface{}{
+		&model.Role{},
+		&model.Setting{},
+		&model.User{},
+	}
+}
+
+// BackupManifest 备份清单
+type BackupManifest struct {
+	FormatVersion int           `json:"format_version"`
+	SchemaVersion string        `json:"schema_version"` // 导出时最后一个已执行迁移的 ID
+	Environment   string        `json:"environment"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Tables        []BackupTable `json:"tables"`
+}
+
+// BackupTable 备份中的一张表
+type BackupTable struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Rows   int64  `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// WriteBackup 将所有业务表导出为 tar.gz 归档
+// 归档包含 manifest.json 和每张表一个 JSON Lines 文件，每行是按列名编码的一条记录（包括软删除的记录）
+func WriteBackup(db *gorm.DB, w io.Writer, env string) (*BackupManifest, error) {
+	version, err := currentSchemaVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &BackupManifest{
+		FormatVersion: BackupFormatVersion,
+		SchemaVersion: version,
+		Environment:   env,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	// tar 需要预先知道文件大小，表数据先写入临时文件
+	var files []*os.File
+	defer func() {
+		for _, f := range files {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	for _, m := range backupModels() {
+		s, err := parseModel(db, m)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.CreateTemp("", "backup-"+s.Table+"-*.jsonl")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temp file: %w", err)
+		}
+		files = append(files, f)
+
+		h := sha256.New()
+		rows, err := exportTable(db, s, io.MultiWriter(f, h))
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", s.Table, err)
+		}
+		manifest.Tables = append(manifest.Tables, BackupTable{
+			Name:   s.Table,
+			File:   s.Table + ".jsonl",
+			Rows:   rows,
+			SHA256: hex.EncodeToString(h.Sum(nil)),
+		})
+		logger.Info("数据表已导出", zap.String("table", s.Table), zap.Int64("rows", rows))
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tw, backupManifestName, int64(len(data)), manifest.CreatedAt, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	for i, f := range files {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := writeTarFile(tw, manifest.Tables[i].File, info.Size(), manifest.CreatedAt, f); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ReadBackupManifest 只读取归档中的清单
+func ReadBackupManifest(r io.Reader) (*BackupManifest, error) {
+	_, manifest, err := openBackup(r)
+	return manifest, err
+}
+
+// CheckBackupCompatible 检查归档是否可以恢复到当前版本的代码
+// 归档的 schema_version 必须是当前代码已知的迁移（为空表示导出时使用 AutoMigrate）；
+// 恢复时数据按当前模型写入，因此旧版本的归档可以恢复到迁移到最新版本的数据库
+func CheckBackupCompatible(manifest *BackupManifest) error {
+	if manifest.SchemaVersion == "" {
+		return nil
+	}
+	// 各方言的迁移 ID 相同，按通用迁移文件检查
+	all, err := Migrations("")
+	if err != nil {
+		return err
+	}
+	if _, ok := findMigration(all, manifest.SchemaVersion); !ok {
+		return fmt.Errorf("backup schema version %s is unknown to this build, restore it with a newer release", manifest.SchemaVersion)
+	}
+	return nil
+}
+
+// RestoreBackup 将归档恢复到空数据库
+// 调用前数据库结构需要已迁移到最新版本；所有数据在一个事务中写入
+func RestoreBackup(db *gorm.DB, r io.Reader) (*BackupManifest, error) {
+	tr, manifest, err := openBackup(r)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]*schema.Schema)
+	for _, m := range backupModels() {
+		s, err := parseModel(db, m)
+		if err != nil {
+			return nil, err
+		}
+		schemas[s.Table] = s
+	}
+	for _, t := range manifest.Tables {
+		if schemas[t.Name] == nil {
+			return nil, fmt.Errorf("backup contains unknown table %s", t.Name)
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range manifest.Tables {
+			var count int64
+			if err := tx.Table(t.Name).Count(&count).Error; err != nil {
+				return fmt.Errorf("count %s: %w", t.Name, err)
+			}
+			if count > 0 {
+				return fmt.Errorf("%w: table %s has %d rows", ErrDatabaseNotEmpty, t.Name, count)
+			}
+		}
+
+		for _, t := range manifest.Tables {
+			hdr, err := tr.Next()
+			if err != nil {
+				return fmt.Errorf("read %s: %w", t.File, err)
+			}
+			if hdr.Name != t.File {
+				return fmt.Errorf("unexpected file %s in backup, expected %s", hdr.Name, t.File)
+			}
+
+			h := sha256.New()
+			rows, err := importTable(tx, schemas[t.Name], io.TeeReader(tr, h))
+			if err != nil {
+				return fmt.Errorf("restore %s: %w", t.Name, err)
+			}
+			if err := verifyBackupTable(t, rows, h); err != nil {
+				return err
+			}
+			if err := resetSequence(tx, schemas[t.Name]); err != nil {
+				return err
+			}
+			logger.Info("数据表已恢复", zap.String("table", t.Name), zap.Int64("rows", rows))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// openBackup 打开归档并读取清单
+func openBackup(r io.Reader) (*tar.Reader, *BackupManifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid backup archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid backup archive: %w", err)
+	}
+	if hdr.Name != backupManifestName {
+		return nil, nil, fmt.Errorf("invalid backup archive: first file is %s, expected %s", hdr.Name, backupManifestName)
+	}
+
+	var manifest BackupManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	if manifest.FormatVersion > BackupFormatVersion {
+		return nil, nil, fmt.Errorf("backup format version %d is newer than supported version %d", manifest.FormatVersion, BackupFormatVersion)
+	}
+	return tr, &manifest, nil
+}
+
+// verifyBackupTable 校验恢复的行数和文件校验和
+func verifyBackupTable(t BackupTable, rows int64, h hash.Hash) error {
+	if rows != t.Rows {
+		return fmt.Errorf("table %s: restored %d rows, manifest says %d", t.Name, rows, t.Rows)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != t.SHA256 {
+		return fmt.Errorf("table %s: checksum mismatch", t.Name)
+	}
+	return nil
+}
+
+// exportTable 分批导出表数据（FindInBatches 按主键排序），每行一个以列名为键的 JSON 对象
+func exportTable(db *gorm.DB, s *schema.Schema, w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	ctx := db.Statement.Context
+
+	var rows int64
+	batch := reflect.New(reflect.SliceOf(s.ModelType))
+	err := db.Unscoped().Model(reflect.New(s.ModelType).Interface()).
+		FindInBatches(batch.Interface(), backupBatchSize, func(tx *gorm.DB, _ int) error {
+			items := batch.Elem()
+			for i := 0; i < items.Len(); i++ {
+				record := make(map[string]interface{}, len(s.DBNames))
+				for _, name := range s.DBNames {
+					value, _ := s.FieldsByDBName[name].ValueOf(ctx, items.Index(i))
+					record[name] = value
+				}
+				if err := enc.Encode(record); err != nil {
+					return err
+				}
+				rows++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return 0, err
+	}
+	return rows, bw.Flush()
+}
+
+// importTable 读取 JSON Lines 数据并通过模型分批写入
+// 每列按模型字段类型解码，未知列会被忽略，缺失列使用零值
+func importTable(tx *gorm.DB, s *schema.Schema, r io.Reader) (int64, error) {
+	dec := json.NewDecoder(r)
+	ctx := tx.Statement.Context
+
+	var rows int64
+	batch := reflect.MakeSlice(reflect.SliceOf(s.ModelType), 0, backupBatchSize)
+	flush := func() error {
+		if batch.Len() == 0 {
+			return nil
+		}
+		ptr := reflect.New(batch.Type())
+		ptr.Elem().Set(batch)
+		if err := tx.Create(ptr.Interface()).Error; err != nil {
+			return err
+		}
+		batch = batch.Slice(0, 0)
+		return nil
+	}
+
+	for {
+		var record map[string]json.RawMessage
+		if err := dec.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return rows, fmt.Errorf("line %d: %w", rows+1, err)
+		}
+
+		item := reflect.New(s.ModelType).Elem()
+		for name, raw := range record {
+			field := s.FieldsByDBName[name]
+			if field == nil {
+				continue
+			}
+			value := reflect.New(field.FieldType)
+			if err := json.Unmarshal(raw, value.Interface()); err != nil {
+				return rows, fmt.Errorf("line %d column %s: %w", rows+1, name, err)
+			}
+			if err := field.Set(ctx, item, value.Elem().Interface()); err != nil {
+				return rows, fmt.Errorf("line %d column %s: %w", rows+1, name, err)
+			}
+		}
+		batch = reflect.Append(batch, item)
+		rows++
+
+		if batch.Len() == backupBatchSize {
+			if err := flush(); err != nil {
+				return rows, err
+			}
+		}
+	}
+	return rows, flush()
+}
+
+// resetSequence 恢复显式主键后同步 PostgreSQL 自增序列，避免之后插入时主键冲突
+func resetSequence(tx *gorm.DB, s *schema.Schema) error {
+	if tx.Dialector.Name() != "postgres" || s.PrioritizedPrimaryField == nil || !s.PrioritizedPrimaryField.AutoIncrement {
+		return nil
+	}
+	pk := s.PrioritizedPrimaryField.DBName
+	err := tx.Exec("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX(?), 0) + 1, false) FROM ?",
+		s.Table, pk, clause.Column{Name: pk}, clause.Table{Name: s.Table}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reset sequence for %s: %w", s.Table, err)
+	}
+	return nil
+}
+
+// currentSchemaVersion 返回最后一个已执行迁移的 ID，没有执行过版本化迁移时返回空字符串
+func currentSchemaVersion(db *gorm.DB) (string, error) {
+	_, records, err := readMigrationState(db)
+	if err != nil {
+		return "", err
+	}
+	ids := make([]string, 0, len(records))
+	for _, r := range records {
+		ids = append(ids, r.MigrationID)
+	}
+	if len(ids) == 0 {
+		return "", nil
+	}
+	sort.Strings(ids)
+	return ids[len(ids)-1], nil
+}
+
+// parseModel 解析模型结构
+func parseModel(db *gorm.DB, m interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(m); err != nil {
+		return nil, fmt.Errorf("failed to parse model %T: %w", m, err)
+	}
+	return stmt.Schema, nil
+}
+
+// writeTarFile 向归档写入一个文件
+func writeTarFile(tw *tar.Writer, name string, size int64, modTime time.Time, r io.Reader) error {
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: size, ModTime: modTime}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
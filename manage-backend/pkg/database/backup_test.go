@@ -0,0 +1,60 @@
+package database
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newBackupTestDB 创建包含全部业务表和迁移记录表的 SQLite 内存数据库
+func newBackupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newSQLiteTestDB(t)
+	require.NoError(t, db.AutoMigrate(append(schemaModels(), &MigrationRecord{})...))
+	return db
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	src := newBackupTestDB(t)
+	require.NoError(t, src.Create(&MigrationRecord{MigrationID: "000002_create_roles_and_settings", ExecutedAt: time.Now()}).Error)
+	require.NoError(t, src.Create(&MigrationRecord{MigrationID: "000001_create_users_table", ExecutedAt: time.Now()}).Error)
+	require.NoError(t, src.Create(&model.Role{Name: "admin", DisplayName: "管理员"}).Error)
+	require.NoError(t, src.Create(&model.Setting{Key: "site.name", Value: "demo"}).Error)
+	users := []model.User{
+		{Username: "alice", Email: "alice@example.com", Password: "hash-a", Role: "admin", Status: "active"},
+		{Username: "bob", Email: "bob@example.com", Password: "hash-b", Role: "user", Status: "active"},
+	}
+	require.NoError(t, src.Create(&users).Error)
+	require.NoError(t, src.Delete(&users[1]).Error)
+
+	var buf bytes.Buffer
+	manifest, err := WriteBackup(src, &buf, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "000002_create_roles_and_settings", manifest.SchemaVersion)
+	require.Len(t, manifest.Tables, 3)
+	assert.Equal(t, int64(2), manifest.Tables[2].Rows)
+
+	read, err := ReadBackupManifest(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Tables, read.Tables)
+
+	dst := newBackupTestDB(t)
+	_, err = RestoreBackup(dst, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	var restored []model.User
+	require.NoError(t, dst.Unscoped().Order("id").Find(&restored).Error)
+	require.Len(t, restored, 2)
+	assert.Equal(t, users[0].ID, restored[0].ID)
+	assert.Equal(t, "hash-a", restored[0].Password)
+	assert.True(t, restored[1].DeletedAt.Valid)
+
+	// 目标库非空时拒绝恢复
+	_, err = RestoreBackup(dst, bytes.NewReader(buf.Bytes()))
+	assert.ErrorIs(t, err, ErrDatabaseNotEmpty)
+}
@@ -1,46 +1,272 @@
 package database
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
+// mysqlTLSConfigName 注册到 MySQL 驱动的 TLS 配置名称前缀，按主机区分（副本的 ServerName 不同）
+const mysqlTLSConfigName = "manage-backend"
+
 func Init(cfg config.Database) (*gorm.DB, error) {
-	// 构建 DSN，如果指定了模式则添加 search_path
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai",
-		cfg.Host,
-		cfg.User,
-		cfg.Password,
-		cfg.Name,
-		cfg.Port,
-	)
-	
-	// 如果指定了非默认模式，添加到 search_path
-	if cfg.Schema != "" && cfg.Schema != "public" {
-		dsn += fmt.Sprintf(" search_path=%s", cfg.Schema)
+	cfg = cfg.WithDefaults()
+
+	dialector, err := Dialector(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+	// SQL 日志输出到 zap，级别由 database.log.level 控制，为空时跟随全局级别
+	if cfg.Log.Level != "" && cfg.Log.Level != config.DatabaseLogSilent {
+		if err := logger.SetLevel(string(sqlLog), cfg.Log.Level, 0); err != nil {
+			return nil, fmt.Errorf("invalid database log level: %w", err)
+		}
+	}
+
+	db, err := openWithRetry(dialector, &gorm.Config{
+		Logger: NewGormLogger(cfg.Log),
+	}, cfg.ConnectRetry)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, err
 	}
 
+	if err := configurePool(db, cfg); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// configurePool 按配置设置连接池参数
+func configurePool(db *gorm.DB, cfg config.Database) error {
 	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
 	}
 
-	// Set connection pool settings
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetMaxIdleConns(cfg.Pool.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.Pool.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.Pool.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.Pool.ConnMaxIdleTime)
 
-	return db, nil
-}
\ No newline at end of file
+	// SQLite 同一时间只允许一个写入；内存数据库每个连接都是独立的库，只能使用单个连接
+	if db.Dialector.Name() == config.DatabaseDriverSQLite {
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	}
+	return nil
+}
+
+// openWithRetry 连接数据库，失败时按指数退避重试（如容器启动时数据库尚未就绪）
+func openWithRetry(dialector gorm.Dialector, gormCfg *gorm.Config, retry config.DatabaseRetryConfig) (*gorm.DB, error) {
+	interval := retry.InitialInterval
+	for attempt := 1; ; attempt++ {
+		db, err := gorm.Open(dialector, gormCfg)
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("数据库连接成功", zap.Int("attempt", attempt))
+			}
+			return db, nil
+		}
+
+		// 连接失败时 GORM 已经创建了连接池，关闭后再重试
+		if db != nil {
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				sqlDB.Close()
+			}
+		}
+		if attempt >= retry.MaxAttempts {
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		}
+
+		logger.Warn("数据库连接失败，稍后重试",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", retry.MaxAttempts),
+			zap.Duration("retry_in", interval),
+			zap.Error(err))
+		time.Sleep(interval)
+
+		interval *= 2
+		if retry.MaxInterval > 0 && interval > retry.MaxInterval {
+			interval = retry.MaxInterval
+		}
+	}
+}
+
+// Dialector 根据配置的驱动创建 GORM Dialector
+func Dialector(cfg config.Database) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case "", config.DatabaseDriverPostgres:
+		return postgres.Open(postgresDSN(cfg)), nil
+
+	case config.DatabaseDriverMySQL:
+		dsn, err := mysqlDSN(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return mysql.Open(dsn), nil
+
+	case config.DatabaseDriverSQLite:
+		// 使用 mattn/go-sqlite3，需要启用 cgo
+		name := cfg.Name
+		if name == "" {
+			name = ":memory:"
+		}
+		return sqlite.Open(fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", name)), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %q", cfg.Driver)
+	}
+}
+
+// postgresDSN 构建 PostgreSQL 的 key=value 连接串，值统一加引号以支持空格等特殊字符
+func postgresDSN(cfg config.Database) string {
+	params := [][2]string{
+		{"host", cfg.Host},
+		{"port", cfg.Port},
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"dbname", cfg.Name},
+		{"sslmode", cfg.SSLMode},
+		{"sslrootcert", cfg.SSLRootCert},
+		{"sslcert", cfg.SSLCert},
+		{"sslkey", cfg.SSLKey},
+		{"TimeZone", cfg.TimeZone},
+		{"application_name", cfg.ApplicationName},
+	}
+	if cfg.StatementTimeout > 0 {
+		params = append(params, [2]string{"statement_timeout", strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)})
+	}
+	// 如果指定了非默认模式，添加到 search_path
+	if cfg.Schema != "" && cfg.Schema != "public" {
+		params = append(params, [2]string{"search_path", cfg.Schema})
+	}
+
+	var b strings.Builder
+	for _, p := range params {
+		if p[1] == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(p[1])
+		fmt.Fprintf(&b, "%s='%s'", p[0], value)
+	}
+	return b.String()
+}
+
+// mysqlDSN 构建 MySQL 连接串
+func mysqlDSN(cfg config.Database) (string, error) {
+	loc, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return "", fmt.Errorf("failed to load time zone: %w", err)
+	}
+
+	mc := mysqldriver.NewConfig()
+	mc.User = cfg.User
+	mc.Passwd = cfg.Password
+	mc.Net = "tcp"
+	mc.Addr = net.JoinHostPort(cfg.Host, cfg.Port)
+	mc.DBName = cfg.Name
+	mc.ParseTime = true
+	mc.Loc = loc // 只影响 DATETIME 的解析，会话时区使用服务端设置
+	mc.Params = map[string]string{"charset": "utf8mb4"}
+	if cfg.StatementTimeout > 0 {
+		mc.Params["max_execution_time"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if cfg.ApplicationName != "" {
+		mc.ConnectionAttributes = "program_name:" + cfg.ApplicationName
+	}
+
+	switch cfg.SSLMode {
+	case "", config.DatabaseSSLDisable:
+	case config.DatabaseSSLAllow, config.DatabaseSSLPrefer:
+		mc.TLSConfig = "preferred"
+	default:
+		tlsCfg, err := tlsConfig(cfg)
+		if err != nil {
+			return "", err
+		}
+		name := mysqlTLSConfigName + "-" + cfg.Host
+		if err := mysqldriver.RegisterTLSConfig(name, tlsCfg); err != nil {
+			return "", fmt.Errorf("failed to register tls config: %w", err)
+		}
+		mc.TLSConfig = name
+	}
+	return mc.FormatDSN(), nil
+}
+
+// tlsConfig 按 require/verify-ca/verify-full 语义构建 TLS 配置（与 PostgreSQL 的 sslmode 一致）
+// - require: 加密但不校验服务端证书
+// - verify-ca: 校验证书由 ssl_root_cert 签发，不校验主机名
+// - verify-full: 同时校验主机名
+func tlsConfig(cfg config.Database) (*tls.Config, error) {
+	tlsCfg := &tls.Config{ServerName: cfg.Host, MinVersion: tls.VersionTLS12}
+
+	if cfg.SSLRootCert != "" {
+		pem, err := os.ReadFile(cfg.SSLRootCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ssl_root_cert: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ssl_root_cert %s", cfg.SSLRootCert)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if cfg.SSLCert != "" || cfg.SSLKey != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.SSLCert, cfg.SSLKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	switch cfg.SSLMode {
+	case config.DatabaseSSLRequire:
+		tlsCfg.InsecureSkipVerify = true
+	case config.DatabaseSSLVerifyCA:
+		// 跳过默认校验（包含主机名），改为只校验证书链
+		tlsCfg.InsecureSkipVerify = true
+		tlsCfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("server presented no certificate")
+			}
+			certs := make([]*x509.Certificate, len(rawCerts))
+			for i, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				certs[i] = cert
+			}
+			opts := x509.VerifyOptions{Roots: tlsCfg.RootCAs, Intermediates: x509.NewCertPool()}
+			for _, cert := range certs[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err := certs[0].Verify(opts)
+			return err
+		}
+	case config.DatabaseSSLVerifyFull:
+	default:
+		return nil, fmt.Errorf("unsupported ssl_mode: %q", cfg.SSLMode)
+	}
+	return tlsCfg, nil
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresDSN(t *testing.T) {
+	cfg := config.Database{
+		Host:             "db",
+		Port:             "5432",
+		User:             "app",
+		Password:         `p@ss 'word`,
+		Name:             "manage",
+		Schema:           "manage_dev",
+		SSLMode:          config.DatabaseSSLVerifyFull,
+		SSLRootCert:      "/etc/ssl/ca.pem",
+		StatementTimeout: 5 * time.Second,
+	}.WithDefaults()
+
+	dsn := postgresDSN(cfg)
+	assert.Contains(t, dsn, `password='p@ss \'word'`)
+	assert.Contains(t, dsn, "sslmode='verify-full'")
+	assert.Contains(t, dsn, "sslrootcert='/etc/ssl/ca.pem'")
+	assert.Contains(t, dsn, "TimeZone='Asia/Shanghai'")
+	assert.Contains(t, dsn, "application_name='go-manage-starter'")
+	assert.Contains(t, dsn, "statement_timeout='5000'")
+	assert.Contains(t, dsn, "search_path='manage_dev'")
+	assert.NotContains(t, dsn, "sslcert=")
+}
+
+func TestMySQLDSN(t *testing.T) {
+	cfg := config.Database{
+		Driver:           config.DatabaseDriverMySQL,
+		Host:             "db",
+		Port:             "3306",
+		User:             "app",
+		Password:         "secret",
+		Name:             "manage",
+		SSLMode:          config.DatabaseSSLPrefer,
+		StatementTimeout: time.Second,
+	}.WithDefaults()
+
+	dsn, err := mysqlDSN(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, dsn, "app:secret@tcp(db:3306)/manage?")
+	assert.Contains(t, dsn, "tls=preferred")
+	assert.Contains(t, dsn, "max_execution_time=1000")
+
+	cfg.SSLMode = config.DatabaseSSLVerifyCA
+	cfg.SSLRootCert = filepath.Join(t.TempDir(), "missing.pem")
+	_, err = mysqlDSN(cfg)
+	assert.ErrorContains(t, err, "ssl_root_cert")
+}
+
+func TestInitRetriesThenFails(t *testing.T) {
+	silenceLogger(t)
+
+	_, err := Init(config.Database{
+		Driver: config.DatabaseDriverSQLite,
+		Name:   filepath.Join(t.TempDir(), "missing", "app.db"),
+		ConnectRetry: config.DatabaseRetryConfig{
+			MaxAttempts:     3,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     2 * time.Millisecond,
+		},
+		Log: config.DatabaseLogConfig{Level: config.DatabaseLogSilent},
+	})
+	assert.ErrorContains(t, err, "after 3 attempts")
+}
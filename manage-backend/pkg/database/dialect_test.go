@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSQLMigrationsPrefersDialectFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_a.up.sql":        {Data: []byte("generic up")},
+		"000001_a.up.mysql.sql":  {Data: []byte("mysql up")},
+		"000001_a.down.sql":      {Data: []byte("generic down")},
+		"000001_a.up.sqlite.sql": {Data: []byte("sqlite up")},
+	}
+
+	for dialect, want := range map[string]string{"postgres": "generic up", "mysql": "mysql up", "sqlite": "sqlite up"} {
+		all, err := buildMigrations(nil, fsys, dialect)
+		require.NoError(t, err, dialect)
+		require.Len(t, all, 1, dialect)
+		assert.Equal(t, want, all[0].UpSQL, dialect)
+		assert.Equal(t, "generic down", all[0].DownSQL, dialect)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	stmts := splitStatements("CREATE TABLE a (v TEXT DEFAULT ';');\n-- comment; here\nCREATE INDEX i ON a(v);\n")
+	assert.Equal(t, []string{"CREATE TABLE a (v TEXT DEFAULT ';')", "-- comment; here\nCREATE INDEX i ON a(v)"}, stmts)
+}
+
+func TestDialectorRejectsUnknownDriver(t *testing.T) {
+	_, err := Dialector(config.Database{Driver: "oracle"})
+	assert.Error(t, err)
+}
+
+func TestSQLiteVersionedMigrations(t *testing.T) {
+	db := newSQLiteTestDB(t)
+
+	require.NoError(t, MigrateUp(db, ""))
+	issues, err := VerifySchema(db)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	version, err := Ping(context.Background(), db)
+	require.NoError(t, err)
+	assert.NotEmpty(t, version)
+
+	latest, err := CheckMigrations(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, "000002_create_roles_and_settings", latest)
+
+	rolledBack, err := MigrateDown(db, 2)
+	require.NoError(t, err)
+	assert.Len(t, rolledBack, 2)
+	assert.False(t, db.Migrator().HasTable("users"))
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 种子数据类型，用于 SeedOptions.Only
+const (
+	FixtureRoles    = "roles"
+	FixtureSettings = "settings"
+	FixtureUsers    = "users"
+)
+
+// fixtureKinds 所有种子数据类型，按写入顺序排列（角色先于引用它的用户）
+var fixtureKinds = []string{FixtureRoles, FixtureSettings, FixtureUsers}
+
+// Fixtures 种子数据
+// 一个目录下的多个文件会合并，同一个文件可以同时包含多种数据
+type Fixtures struct {
+	Roles    []RoleFixture    `yaml:"roles" json:"roles"`
+	Settings []SettingFixture `yaml:"settings" json:"settings"`
+	Users    []UserFixture    `yaml:"users" json:"users"`
+}
+
+// RoleFixture 角色种子数据，按 Name 更新或插入
+type RoleFixture struct {
+	Name        string `yaml:"name" json:"name"`
+	DisplayName string `yaml:"display_name" json:"display_name"`
+	Description string `yaml:"description" json:"description"`
+}
+
+// SettingFixture 系统设置种子数据，按 Key 更新或插入
+type SettingFixture struct {
+	Key         string `yaml:"key" json:"key"`
+	Value       string `yaml:"value" json:"value"`
+	Description string `yaml:"description" json:"description"`
+}
+
+// UserFixture 用户种子数据，按 Username 更新或插入
+// Password 为明文，写入前进行哈希；只在新建用户时设置，已存在用户的密码不会被覆盖。
+// 支持 ${ENV} 形式引用环境变量，避免在文件中保存生产环境密码
+type UserFixture struct {
+	Username string `yaml:"username" json:"username"`
+	Email    string `yaml:"email" json:"email"`
+	Password string `yaml:"password" json:"password"`
+	Role     string `yaml:"role" json:"role"`
+	Status   string `yaml:"status" json:"status"`
+}
+
+// LoadFixtures 加载目录下所有 .yaml/.yml/.json 种子文件，按文件名顺序合并
+// 目录不存在时返回空数据
+func LoadFixtures(dir string) (*Fixtures, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return &Fixtures{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture dir %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+
+	fixtures := &Fixtures{}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %w", name, err)
+		}
+
+		// JSON 是 YAML 的子集，统一使用 YAML 解析
+		var f Fixtures
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", name, err)
+		}
+		fixtures.Roles = append(fixtures.Roles, f.Roles...)
+		fixtures.Settings = append(fixtures.Settings, f.Settings...)
+		fixtures.Users = append(fixtures.Users, f.Users...)
+	}
+
+	if err := fixtures.validate(); err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// validate 检查必填字段和重复项
+func (f *Fixtures) validate() error {
+	roles := make(map[string]bool, len(f.Roles))
+	for _, r := range f.Roles {
+		if r.Name == "" {
+			return fmt.Errorf("role fixture without name")
+		}
+		if roles[r.Name] {
+			return fmt.Errorf("duplicate role fixture %q", r.Name)
+		}
+		roles[r.Name] = true
+	}
+
+	settings := make(map[string]bool, len(f.Settings))
+	for _, s := range f.Settings {
+		if s.Key == "" {
+			return fmt.Errorf("setting fixture without key")
+		}
+		if settings[s.Key] {
+			return fmt.Errorf("duplicate setting fixture %q", s.Key)
+		}
+		settings[s.Key] = true
+	}
+
+	users := make(map[string]bool, len(f.Users))
+	for _, u := range f.Users {
+		if u.Username == "" || u.Email == "" {
+			return fmt.Errorf("user fixture requires username and email")
+		}
+		if users[u.Username] {
+			return fmt.Errorf("duplicate user fixture %q", u.Username)
+		}
+		users[u.Username] = true
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFixturesMergesFilesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "01_roles.yaml"), []byte("roles:\n  - name: admin\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "02_users.json"),
+		[]byte(`{"users":[{"username":"alice","email":"alice@example.com","password":"${SEED_PW}"}]}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o644))
+
+	fixtures, err := LoadFixtures(dir)
+	require.NoError(t, err)
+	require.Len(t, fixtures.Roles, 1)
+	require.Len(t, fixtures.Users, 1)
+	assert.Equal(t, "admin", fixtures.Roles[0].Name)
+	assert.Equal(t, "${SEED_PW}", fixtures.Users[0].Password)
+
+	missing, err := LoadFixtures(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, missing.Users)
+}
+
+func TestLoadFixturesRejectsDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("settings:\n  - key: site.name\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("settings:\n  - key: site.name\n"), 0o644))
+
+	_, err := LoadFixtures(dir)
+	assert.ErrorContains(t, err, "duplicate setting")
+}
+
+func TestRepositorySeedFilesAreValid(t *testing.T) {
+	for _, env := range []string{"development", "test", "production"} {
+		fixtures, err := LoadFixtures(filepath.Join("..", "..", "config", "seeds", env))
+		require.NoError(t, err, env)
+		assert.NotEmpty(t, fixtures.Users, env)
+	}
+}
+
+func TestSelectFixtureKinds(t *testing.T) {
+	kinds, err := selectFixtureKinds([]string{"users", " roles"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{FixtureRoles, FixtureUsers}, kinds)
+
+	_, err = selectFixtureKinds([]string{"groups"})
+	assert.Error(t, err)
+}
+
+func TestSeedRejectsUnsafeOptionsInProduction(t *testing.T) {
+	// 校验在访问数据库之前完成
+	err := Seed(nil, "production", SeedOptions{Truncate: true})
+	assert.ErrorContains(t, err, "truncate is not allowed")
+
+	err = Seed(nil, "production", SeedOptions{FakeUsers: 10})
+	assert.ErrorContains(t, err, "fake users are not allowed")
+}
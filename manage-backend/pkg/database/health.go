@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// Ping 检查数据库连接，成功时返回数据库版本
+func Ping(ctx context.Context, db *gorm.DB) (string, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return "", fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return "", err
+	}
+
+	// 配置了只读副本时也检查主库
+	var version string
+	if err := db.WithContext(ctx).Clauses(dbresolver.Write).Raw(versionQuery(db.Dialector.Name())).Scan(&version).Error; err != nil {
+		return "", fmt.Errorf("failed to query server version: %w", err)
+	}
+	return version, nil
+}
+
+// versionQuery 返回查询数据库版本的 SQL
+func versionQuery(dialect string) string {
+	switch dialect {
+	case "mysql":
+		return "SELECT VERSION()"
+	case "sqlite":
+		return "SELECT sqlite_version()"
+	default:
+		return "SHOW server_version"
+	}
+}
+
+// CheckMigrations 检查是否有未执行的迁移，成功时返回最后一个已执行的迁移 ID
+func CheckMigrations(ctx context.Context, db *gorm.DB) (string, error) {
+	status, err := GetMigrationStatus(db.WithContext(ctx).Clauses(dbresolver.Write).Session(&gorm.Session{}))
+	if err != nil {
+		return "", err
+	}
+
+	var latest string
+	var pending []string
+	for _, s := range status {
+		if s.Executed {
+			latest = s.ID
+		} else {
+			pending = append(pending, s.ID)
+		}
+	}
+	if len(pending) > 0 {
+		return latest, fmt.Errorf("%d pending migrations: %s", len(pending), strings.Join(pending, ", "))
+	}
+	return latest, nil
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// silenceLogger 在测试期间关闭日志输出，结束后恢复
+func silenceLogger(t *testing.T) {
+	t.Helper()
+	original := logger.Logger
+	logger.Logger = zap.NewNop()
+	t.Cleanup(func() { logger.Logger = original })
+}
+
+// newSQLiteTestDB 创建 SQLite 内存数据库，并在测试期间关闭日志输出
+func newSQLiteTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	silenceLogger(t)
+
+	db, err := Init(config.Database{
+		Driver: config.DatabaseDriverSQLite,
+		Name:   ":memory:",
+		Log:    config.DatabaseLogConfig{Level: config.DatabaseLogSilent},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
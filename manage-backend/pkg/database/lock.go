@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// migrationLockKey 迁移使用的 PostgreSQL advisory lock 键（"gms:migrations" 的 FNV-1a 哈希）
+const migrationLockKey int64 = 0x0df2dbc6fb878bb1
+
+// migrationLockName 迁移使用的 MySQL 命名锁
+const migrationLockName = "gms:migrations"
+
+// migrationLockTimeout 等待迁移锁的最长时间
+const migrationLockTimeout = 2 * time.Minute
+
+// minMigrationLockConns 持有迁移锁时需要的最少连接数：一个持锁，至少一个执行迁移
+const minMigrationLockConns = 2
+
+// withMigrationLock 持有迁移锁执行 fn，防止多个实例同时运行迁移
+// PostgreSQL 使用会话级 advisory lock，MySQL 使用 GET_LOCK 命名锁，两者都与单个连接绑定，
+// 因此从连接池中取出一个专用连接加锁，fn 执行完后在同一连接上释放
+// fn 使用连接池中的其他连接，连接池上限不足时临时调高，避免互相等待
+// SQLite 只能被单机访问，直接执行 fn
+func withMigrationLock(db *gorm.DB, fn func() error) error {
+	var lockSQL, unlockSQL string
+	var args []interface{}
+	switch db.Dialector.Name() {
+	case "postgres":
+		lockSQL, unlockSQL = "SELECT pg_advisory_lock($1)", "SELECT pg_advisory_unlock($1)"
+		args = []interface{}{migrationLockKey}
+	case "mysql":
+		lockSQL, unlockSQL = "SELECT GET_LOCK(?, ?)", "SELECT RELEASE_LOCK(?)"
+		args = []interface{}{migrationLockName, int(migrationLockTimeout.Seconds())}
+	default:
+		return fn()
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	defer reserveLockConn(sqlDB)()
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrationLockTimeout)
+	defer cancel()
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	if db.Dialector.Name() == "mysql" {
+		// GET_LOCK 超时返回 0 而不是错误
+		var acquired sql.NullInt64
+		if err := conn.QueryRowContext(ctx, lockSQL, args...).Scan(&acquired); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if acquired.Int64 != 1 {
+			return fmt.Errorf("failed to acquire migration lock: timed out after %s", migrationLockTimeout)
+		}
+	} else if _, err := conn.ExecContext(ctx, lockSQL, args...); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	logger.Info("已获取迁移锁", zap.Duration("waited", time.Since(start)))
+
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), unlockSQL, args[0]); err != nil {
+			logger.Error("释放迁移锁失败", zap.Error(err))
+		}
+	}()
+
+	return fn()
+}
+
+// reserveLockConn 连接池上限小于 minMigrationLockConns 时临时调高，返回恢复原上限的函数
+func reserveLockConn(sqlDB *sql.DB) (restore func()) {
+	maxOpen := sqlDB.Stats().MaxOpenConnections
+	if maxOpen <= 0 || maxOpen >= minMigrationLockConns {
+		return func() {}
+	}
+
+	logger.Warn("连接池上限不足以同时持有迁移锁和执行迁移，迁移期间临时调高",
+		zap.Int("max_open_conns", maxOpen),
+		zap.Int("required", minMigrationLockConns))
+	sqlDB.SetMaxOpenConns(minMigrationLockConns)
+	return func() { sqlDB.SetMaxOpenConns(maxOpen) }
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveLockConn(t *testing.T) {
+	sqlDB, err := newSQLiteTestDB(t).DB()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	sqlDB.SetMaxOpenConns(1)
+	restore := reserveLockConn(sqlDB)
+	assert.Equal(t, minMigrationLockConns, sqlDB.Stats().MaxOpenConnections)
+
+	// 一个连接持锁时，迁移仍能从连接池取得连接
+	conn, err := sqlDB.Conn(ctx)
+	require.NoError(t, err)
+	queryCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, sqlDB.PingContext(queryCtx))
+	require.NoError(t, conn.Close())
+
+	restore()
+	assert.Equal(t, 1, sqlDB.Stats().MaxOpenConnections)
+
+	// 未限制或已足够时不修改
+	for _, maxOpen := range []int{0, 5} {
+		sqlDB.SetMaxOpenConns(maxOpen)
+		reserveLockConn(sqlDB)()
+		assert.Equal(t, maxOpen, sqlDB.Stats().MaxOpenConnections)
+	}
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// sqlLog SQL 日志器，级别由 database.log.level 设置，运行时可通过 logger.SetLevel("gorm", ...) 调整
+const sqlLog = logger.Component("gorm")
+
+// GormLogger 将 GORM 日志输出到 zap
+// 普通 SQL 使用 debug 级别，慢查询使用 warn 级别，执行失败使用 error 级别
+type GormLogger struct {
+	level                gormlogger.LogLevel
+	slowThreshold        time.Duration
+	logRecordNotFound    bool
+	parameterizedQueries bool
+}
+
+var (
+	_ gormlogger.Interface = (*GormLogger)(nil)
+	_ gorm.ParamsFilter    = (*GormLogger)(nil)
+)
+
+// NewGormLogger 创建 GORM 日志适配器，cfg 中未设置的字段使用默认值
+func NewGormLogger(cfg config.DatabaseLogConfig) *GormLogger {
+	if cfg.SlowThreshold <= 0 {
+		cfg.SlowThreshold = config.GetDefaultDatabaseLogConfig().SlowThreshold
+	}
+
+	level := gormlogger.Info
+	if cfg.Level == config.DatabaseLogSilent {
+		level = gormlogger.Silent
+	}
+
+	return &GormLogger{
+		level:                level,
+		slowThreshold:        cfg.SlowThreshold,
+		logRecordNotFound:    cfg.LogRecordNotFound,
+		parameterizedQueries: cfg.ParameterizedQueries,
+	}
+}
+
+// LogMode 返回指定 GORM 日志级别的副本（db.Debug() 等会调用）
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	nl := *l
+	nl.level = level
+	return &nl
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.log(ctx, zapcore.InfoLevel, fmt.Sprintf(msg, data...), zap.String("caller", utils.FileWithLineNum()))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.log(ctx, zapcore.WarnLevel, fmt.Sprintf(msg, data...), zap.String("caller", utils.FileWithLineNum()))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.log(ctx, zapcore.ErrorLevel, fmt.Sprintf(msg, data...), zap.String("caller", utils.FileWithLineNum()))
+	}
+}
+
+// Trace 记录一条 SQL 的执行情况，慢查询无论日志级别如何都会计入指标
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	slow := elapsed > l.slowThreshold
+
+	var (
+		lvl zapcore.Level
+		msg string
+	)
+	switch {
+	case err != nil && l.level >= gormlogger.Error && (l.logRecordNotFound || !errors.Is(err, gorm.ErrRecordNotFound)):
+		lvl, msg = zapcore.ErrorLevel, "SQL 执行失败"
+	case slow && l.level >= gormlogger.Warn:
+		lvl, msg = zapcore.WarnLevel, "慢查询"
+	case l.level >= gormlogger.Info:
+		lvl, msg = zapcore.DebugLevel, "SQL"
+	default:
+		return
+	}
+
+	enabled := sqlLog.Enabled(lvl)
+	if !enabled && !slow {
+		return
+	}
+
+	sql, rows := fc()
+	if slow {
+		metrics.RecordSlowQuery(sqlOperation(sql))
+	}
+	if !enabled {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("elapsed", elapsed),
+		zap.String("caller", utils.FileWithLineNum()),
+	}
+	if slow {
+		fields = append(fields, zap.Duration("slow_threshold", l.slowThreshold))
+	}
+	if lvl == zapcore.ErrorLevel {
+		fields = append(fields, zap.Error(err))
+	}
+	l.log(ctx, lvl, msg, fields...)
+}
+
+// ParamsFilter 开启 parameterized_queries 时日志中的 SQL 不带参数值
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.parameterizedQueries {
+		return sql, nil
+	}
+	return sql, params
+}
+
+// log 输出日志，调用位置由 caller 字段给出（指向业务代码而不是 GORM 内部）
+func (l *GormLogger) log(ctx context.Context, lvl zapcore.Level, msg string, fields ...zap.Field) {
+	logger.FromContext(ctx).Named(string(sqlLog)).WithOptions(zap.WithCaller(false)).Log(lvl, msg, fields...)
+}
+
+// sqlOperation 返回 SQL 语句类型，用作指标标签
+func sqlOperation(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if i := strings.IndexAny(sql, " \t\r\n"); i > 0 {
+		sql = sql[:i]
+	}
+	switch op := strings.ToUpper(sql); op {
+	case "SELECT", "INSERT", "UPDATE", "DELETE":
+		return op
+	default:
+		return "OTHER"
+	}
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func observeSQLLog(t *testing.T, level string) *observer.ObservedLogs {
+	t.Helper()
+	core, logs := observer.New(zapcore.DebugLevel)
+	original := logger.Logger
+	logger.Logger = zap.New(core)
+	require.NoError(t, logger.SetLevel(string(sqlLog), level, 0))
+	t.Cleanup(func() {
+		logger.Logger = original
+		logger.ClearLevel(string(sqlLog))
+	})
+	return logs
+}
+
+func TestGormLoggerTrace(t *testing.T) {
+	logs := observeSQLLog(t, "debug")
+	l := NewGormLogger(config.DatabaseLogConfig{SlowThreshold: 50 * time.Millisecond})
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	fc := func() (string, int64) { return `SELECT * FROM "users" WHERE id = 1`, 1 }
+
+	l.Trace(ctx, time.Now(), fc, nil)
+	l.Trace(ctx, time.Now().Add(-time.Second), fc, nil)
+	l.Trace(ctx, time.Now(), fc, gorm.ErrRecordNotFound)
+	l.Trace(ctx, time.Now(), fc, gorm.ErrInvalidData)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+	assert.Equal(t, "gorm", entries[0].LoggerName)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, `SELECT * FROM "users" WHERE id = 1`, fields["sql"])
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Contains(t, fields["caller"], "logger_test.go")
+
+	assert.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	assert.Equal(t, "慢查询", entries[1].Message)
+
+	// 默认忽略 record not found
+	assert.Equal(t, zapcore.DebugLevel, entries[2].Level)
+	assert.Equal(t, zapcore.ErrorLevel, entries[3].Level)
+}
+
+func TestGormLoggerRespectsLevel(t *testing.T) {
+	logs := observeSQLLog(t, "warn")
+	l := NewGormLogger(config.DatabaseLogConfig{})
+	fc := func() (string, int64) { return "SELECT 1", 1 }
+
+	l.Trace(context.Background(), time.Now(), fc, nil)
+	l.Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+	require.Len(t, logs.All(), 1)
+	assert.Equal(t, zapcore.WarnLevel, logs.All()[0].Level)
+
+	l.LogMode(gormlogger.Silent).Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+	assert.Len(t, logs.All(), 1)
+}
+
+func TestGormLoggerParamsFilter(t *testing.T) {
+	l := NewGormLogger(config.DatabaseLogConfig{ParameterizedQueries: true})
+	sql, params := l.ParamsFilter(context.Background(), "SELECT ?", 1)
+	assert.Equal(t, "SELECT ?", sql)
+	assert.Nil(t, params)
+}
+
+func TestSQLOperation(t *testing.T) {
+	assert.Equal(t, "SELECT", sqlOperation("  select * from users"))
+	assert.Equal(t, "INSERT", sqlOperation("INSERT INTO users"))
+	assert.Equal(t, "OTHER", sqlOperation("CREATE TABLE t"))
+	assert.Equal(t, "OTHER", sqlOperation(""))
+}
@@ -11,15 +11,30 @@ import (
 	"go.uber.org/zap"
 )
 
+// schemaModels 数据库表对应的模型，开发环境的 AutoMigrate 和结构校验使用
+func schemaModels() []interface{} {
+	return []interface{}{
+		&model.User{},
+		&model.Role{},
+		&model.Setting{},
+		// 在这里添加其他模型
+	}
+}
+
+// UsesAutoMigrate 判断环境是否使用 AutoMigrate（开发和测试环境），这些环境不执行也不记录版本化迁移
+func UsesAutoMigrate(environment string) bool {
+	return environment == "development" || environment == "test"
+}
+
 // RunMigrations 根据环境运行不同的迁移策略
 func RunMigrations(db *gorm.DB, cfg *config.Config) error {
 	logger.Info("开始运行数据库迁移", zap.String("environment", cfg.Environment))
 	
-	switch cfg.Environment {
-	case "development", "test":
+	switch {
+	case UsesAutoMigrate(cfg.Environment):
 		// 开发和测试环境使用 AutoMigrate
 		return autoMigrate(db, cfg)
-	case "production":
+	case cfg.Environment == "production":
 		// 生产环境使用版本化迁移
 		return runVersionedMigrations(db)
 	default:
@@ -31,8 +46,8 @@ func RunMigrations(db *gorm.DB, cfg *config.Config) error {
 func autoMigrate(db *gorm.DB, cfg *config.Config) error {
 	logger.Info("开始自动迁移")
 	
-	// 如果配置了非 public 模式，先创建模式
-	if cfg.Database.Schema != "" && cfg.Database.Schema != "public" {
+	// 如果配置了非 public 模式，先创建模式（仅 PostgreSQL 支持模式）
+	if db.Dialector.Name() == config.DatabaseDriverPostgres && cfg.Database.Schema != "" && cfg.Database.Schema != "public" {
 		createSchemaSQL := fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", cfg.Database.Schema)
 		if err := db.Exec(createSchemaSQL).Error; err != nil {
 			logger.Warn("创建数据库模式失败", zap.String("schema", cfg.Database.Schema), zap.Error(err))
@@ -41,10 +56,7 @@ func autoMigrate(db *gorm.DB, cfg *config.Config) error {
 		}
 	}
 	
-	err := db.AutoMigrate(
-		&model.User{},
-		// 在这里添加其他模型
-	)
+	err := db.AutoMigrate(schemaModels()...)
 	
 	if err != nil {
 		return fmt.Errorf("auto migration failed: %w", err)
@@ -56,44 +68,196 @@ func autoMigrate(db *gorm.DB, cfg *config.Config) error {
 
 // runVersionedMigrations 运行版本化迁移（生产环境）
 func runVersionedMigrations(db *gorm.DB) error {
-	logger.Info("开始运行版本化迁移")
-	
+	return MigrateUp(db, "")
+}
+
+// MigrateUp 按版本号顺序执行未执行的迁移，targetID 非空时执行到该迁移（含）为止
+// 每个迁移及其执行记录在同一个事务中提交；整个过程持有迁移锁，多个实例同时启动时只有一个实例执行迁移
+func MigrateUp(db *gorm.DB, targetID string) error {
+	return withMigrationLock(db, func() error {
+		return applyPendingMigrations(db, targetID)
+	})
+}
+
+// applyPendingMigrations 检查已执行迁移的校验和并执行未执行的迁移
+func applyPendingMigrations(db *gorm.DB, targetID string) error {
+	logger.Info("开始运行版本化迁移", zap.String("target", targetID))
+
+	all, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+
 	// 创建迁移记录表
 	if err := db.AutoMigrate(&MigrationRecord{}); err != nil {
 		return fmt.Errorf("failed to create migration table: %w", err)
 	}
-	
-	// 运行所有未执行的迁移
-	for _, migration := range migrations {
-		var record MigrationRecord
-		result := db.Where("migration_id = ?", migration.ID).First(&record)
-		
-		if result.Error == gorm.ErrRecordNotFound {
-			// 执行迁移
-			if err := migration.Up(db); err != nil {
-				return fmt.Errorf("migration %s failed: %w", migration.ID, err)
-			}
-			
-			// 记录迁移
-			record = MigrationRecord{
-				MigrationID: migration.ID,
-				ExecutedAt:  time.Now(),
-			}
-			if err := db.Create(&record).Error; err != nil {
-				return fmt.Errorf("failed to record migration %s: %w", migration.ID, err)
-			}
-			
-			logger.Info("迁移执行成功", zap.String("migration_id", migration.ID))
+	if err := upgradeLegacyRecords(db, all); err != nil {
+		return err
+	}
+
+	var records []MigrationRecord
+	if err := db.Find(&records).Error; err != nil {
+		return fmt.Errorf("failed to get migration records: %w", err)
+	}
+	if err := checkDrift(records, all); err != nil {
+		return err
+	}
+	if err := backfillChecksums(db, records, all); err != nil {
+		return err
+	}
+
+	pending, err := pendingMigrations(all, records, targetID)
+	if err != nil {
+		return err
+	}
+	for _, migration := range pending {
+		if err := applyMigration(db, migration); err != nil {
+			return err
 		}
+		logger.Info("迁移执行成功", zap.String("migration_id", migration.ID), zap.Int64("version", migration.Version))
 	}
-	
-	logger.Info("版本化迁移完成")
+
+	logger.Info("版本化迁移完成", zap.Int("applied", len(pending)))
 	return nil
 }
 
+// MigrateDown 按版本号倒序回滚最近执行的 steps 个迁移，返回已回滚的迁移 ID
+func MigrateDown(db *gorm.DB, steps int) ([]string, error) {
+	var rolledBack []string
+	err := withMigrationLock(db, func() error {
+		targets, err := PlanDown(db, steps)
+		if err != nil {
+			return err
+		}
+		for _, m := range targets {
+			if err := rollback(db, m); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, m.ID)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// RedoMigration 回滚并重新执行最近一次执行的迁移，返回该迁移 ID
+func RedoMigration(db *gorm.DB) (string, error) {
+	var id string
+	err := withMigrationLock(db, func() error {
+		targets, err := PlanDown(db, 1)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no applied migration to redo")
+		}
+		m := targets[0]
+		if err := rollback(db, m); err != nil {
+			return err
+		}
+		if err := applyMigration(db, m); err != nil {
+			return err
+		}
+		id = m.ID
+		logger.Info("迁移重新执行成功", zap.String("migration_id", m.ID))
+		return nil
+	})
+	return id, err
+}
+
+// PlanUp 返回 MigrateUp 将要执行的迁移，不修改数据库（用于 dry-run）
+func PlanUp(db *gorm.DB, targetID string) ([]Migration, error) {
+	all, records, err := readMigrationState(db)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDrift(records, all); err != nil {
+		return nil, err
+	}
+	return pendingMigrations(all, records, targetID)
+}
+
+// PlanDown 返回 MigrateDown 将要回滚的迁移（按回滚顺序），不修改数据库（用于 dry-run）
+func PlanDown(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be positive, got %d", steps)
+	}
+	all, records, err := readMigrationState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]bool, len(records))
+	for _, record := range records {
+		applied[record.MigrationID] = true
+	}
+
+	var targets []Migration
+	for i := len(all) - 1; i >= 0 && len(targets) < steps; i-- {
+		if !applied[all[i].ID] {
+			continue
+		}
+		if all[i].Down == nil {
+			return nil, fmt.Errorf("migration %s is irreversible: no down migration", all[i].ID)
+		}
+		targets = append(targets, all[i])
+	}
+	return targets, nil
+}
+
+// readMigrationState 读取所有迁移和执行记录，旧版迁移 ID 在内存中按映射转换，不写入数据库
+func readMigrationState(db *gorm.DB) ([]Migration, []MigrationRecord, error) {
+	all, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !db.Migrator().HasTable(&MigrationRecord{}) {
+		return all, nil, nil
+	}
+
+	var records []MigrationRecord
+	if err := db.Find(&records).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to get migration records: %w", err)
+	}
+	for i := range records {
+		if newID, ok := legacyMigrationIDs[records[i].MigrationID]; ok {
+			records[i].MigrationID = newID
+		}
+	}
+	return all, records, nil
+}
+
+// pendingMigrations 返回未执行的迁移，targetID 非空时只返回到该迁移（含）为止
+func pendingMigrations(all []Migration, records []MigrationRecord, targetID string) ([]Migration, error) {
+	if targetID != "" {
+		if _, ok := findMigration(all, targetID); !ok {
+			return nil, fmt.Errorf("migration %s not found", targetID)
+		}
+	}
+
+	applied := make(map[string]bool, len(records))
+	for _, record := range records {
+		applied[record.MigrationID] = true
+	}
+
+	var pending []Migration
+	for _, m := range all {
+		if !applied[m.ID] {
+			pending = append(pending, m)
+		}
+		if m.ID == targetID {
+			break
+		}
+	}
+	return pending, nil
+}
+
 // MigrationRecord 迁移记录模型
 type MigrationRecord struct {
 	ID          uint      `gorm:"primaryKey"`
 	MigrationID string    `gorm:"uniqueIndex;not null"`
+	Version     int64     `gorm:"not null;default:0"`
+	Checksum    string    `gorm:"size:64"`
 	ExecutedAt  time.Time `gorm:"not null"`
-}
\ No newline at end of file
+}
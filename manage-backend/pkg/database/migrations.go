@@ -1,107 +1,425 @@
 package database
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	"gorm.io/gorm"
-	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	sqlmigrations "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/migrations"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
-
 // Migration 定义单个迁移的结构
-// 每个迁移需要一个唯一的 ID，以及对应的 Up（执行迁移）和 Down（回滚迁移）方法
+// 每个迁移需要一个唯一的 ID（格式为 NNNNNN_name，数字前缀为版本号），以及对应的 Up（执行迁移）和 Down（回滚迁移）方法。
+// SQL 文件迁移的 UpSQL/DownSQL 为文件内容，Up/Down 由加载器生成。
 type Migration struct {
-	ID   string
-	Up   func(*gorm.DB) error
-	Down func(*gorm.DB) error
+	ID      string
+	Version int64
+	Up      func(*gorm.DB) error
+	Down    func(*gorm.DB) error
+
+	UpSQL   string
+	DownSQL string
 }
 
-// migrations 迁移列表
-// 在这里定义所有需要的迁移，按顺序执行
+// Checksum 返回迁移内容的 SHA-256 校验和
+// SQL 迁移按 up 文件内容计算；Go 迁移无法计算内容，返回空字符串
+func (m Migration) Checksum() string {
+	if m.UpSQL == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(m.UpSQL))
+	return hex.EncodeToString(sum[:])
+}
+
+// migrations Go 代码实现的迁移列表
+// 需要 SQL 无法表达的逻辑（如数据回填）时在这里追加，ID 的版本号与 migrations/ 目录下的 SQL 文件统一排序
 var migrations = []Migration{
-	{
-		ID: "001_create_users_table",
-		Up: func(db *gorm.DB) error {
-			// 创建 users 表
-			return db.AutoMigrate(&model.User{})
-		},
-		Down: func(db *gorm.DB) error {
-			// 删除 users 表
-			return db.Migrator().DropTable(&model.User{})
-		},
-	},
-	// 在这里继续追加其他迁移
+	// {
+	// 	ID: "000002_backfill_user_status",
+	// 	Up: func(db *gorm.DB) error { ... },
+	// 	Down: func(db *gorm.DB) error { ... },
+	// },
+}
+
+// legacyMigrationIDs 旧版迁移 ID 到新 ID 的映射
+// 旧版本使用 Go 代码和 AutoMigrate 创建 users 表，改为 SQL 文件后沿用原有的执行记录，避免重复执行
+var legacyMigrationIDs = map[string]string{
+	"001_create_users_table": "000001_create_users_table",
+}
+
+// ErrChecksumMismatch 已执行的迁移内容被修改
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+// migrationFilePattern SQL 迁移文件名格式，可选的方言后缀表示只用于该数据库
+// 如 000001_create_users_table.up.sql（通用，按 PostgreSQL 语法编写）和 000001_create_users_table.up.mysql.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.(up|down)(?:\.(postgres|mysql|sqlite))?\.sql$`)
+
+// loadSQLMigrations 从文件系统加载指定数据库方言的 SQL 迁移
+// 有该方言专用文件时使用专用文件，否则使用通用文件
+func loadSQLMigrations(fsys fs.FS, dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration files: %w", err)
+	}
+
+	byID := make(map[string]*Migration)
+	overridden := make(map[string]bool) // 已被方言专用文件覆盖的 "<id>.<up|down>"
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+		specific := matches[4] != ""
+		if specific && matches[4] != dialect {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", entry.Name(), err)
+		}
+
+		id := matches[1] + "_" + matches[2]
+		m, ok := byID[id]
+		if !ok {
+			m = &Migration{ID: id, Version: version}
+			byID[id] = m
+		}
+		target := &m.UpSQL
+		if matches[3] == "down" {
+			target = &m.DownSQL
+		}
+		key := id + "." + matches[3]
+		if specific || !overridden[key] {
+			*target = string(content)
+		}
+		if specific {
+			overridden[key] = true
+		}
+	}
+
+	result := make([]Migration, 0, len(byID))
+	for _, m := range byID {
+		if m.UpSQL == "" {
+			return nil, fmt.Errorf("migration %s has no up file", m.ID)
+		}
+		m.Up = execSQL(m.UpSQL)
+		if m.DownSQL != "" {
+			m.Down = execSQL(m.DownSQL)
+		}
+		result = append(result, *m)
+	}
+	return result, nil
+}
+
+// execSQL 返回执行一段 SQL 的迁移函数
+// MySQL 驱动默认不允许一次执行多条语句，按分号拆分后逐条执行；
+// 注意 MySQL 的 DDL 会隐式提交事务，迁移中途失败时已执行的语句不会回滚
+func execSQL(sql string) func(*gorm.DB) error {
+	return func(db *gorm.DB) error {
+		if db.Dialector.Name() != "mysql" {
+			return db.Exec(sql).Error
+		}
+		for _, stmt := range splitStatements(sql) {
+			if err := db.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// splitStatements 按分号拆分 SQL 语句，忽略引号和 -- 注释中的分号
+func splitStatements(sql string) []string {
+	var stmts []string
+	var quote rune
+	start := 0
+	runes := []rune(sql)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == ';':
+			if stmt := strings.TrimSpace(string(runes[start:i])); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+			start = i + 1
+		}
+	}
+	if stmt := strings.TrimSpace(string(runes[start:])); stmt != "" {
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+// migrationVersion 解析迁移 ID 的数字前缀
+func migrationVersion(id string) (int64, error) {
+	for i, r := range id {
+		if r < '0' || r > '9' {
+			if i == 0 || r != '_' {
+				break
+			}
+			return strconv.ParseInt(id[:i], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("migration id %q must start with a numeric version followed by '_'", id)
+}
+
+// buildMigrations 合并 Go 迁移和 SQL 迁移，按版本号排序，版本号或 ID 重复时返回错误
+func buildMigrations(goMigrations []Migration, fsys fs.FS, dialect string) ([]Migration, error) {
+	all, err := loadSQLMigrations(fsys, dialect)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range goMigrations {
+		version, err := migrationVersion(m.ID)
+		if err != nil {
+			return nil, err
+		}
+		m.Version = version
+		all = append(all, m)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	for i := 1; i < len(all); i++ {
+		if all[i].Version == all[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", all[i].Version, all[i-1].ID, all[i].ID)
+		}
+	}
+	return all, nil
+}
+
+// Migrations 返回指定数据库方言（postgres, mysql, sqlite）的所有迁移（Go 迁移和内嵌的 SQL 迁移），按版本号排序
+// 各方言的迁移 ID 相同，只有 SQL 内容不同
+func Migrations(dialect string) ([]Migration, error) {
+	return buildMigrations(migrations, sqlmigrations.FS, dialect)
 }
 
+// findMigration 按 ID 查找迁移
+func findMigration(all []Migration, id string) (Migration, bool) {
+	for _, m := range all {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+// applyMigration 在一个事务中执行迁移并写入迁移记录
+func applyMigration(db *gorm.DB, m Migration) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := m.Up(tx); err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.ID, err)
+		}
+		record := MigrationRecord{
+			MigrationID: m.ID,
+			Version:     m.Version,
+			Checksum:    m.Checksum(),
+			ExecutedAt:  time.Now(),
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", m.ID, err)
+		}
+		return nil
+	})
+}
+
+// upgradeLegacyRecords 将旧版迁移 ID 的执行记录改为新 ID，并补齐版本号和校验和
+func upgradeLegacyRecords(db *gorm.DB, all []Migration) error {
+	for oldID, newID := range legacyMigrationIDs {
+		m, ok := findMigration(all, newID)
+		if !ok {
+			continue
+		}
+		result := db.Model(&MigrationRecord{}).
+			Where("migration_id = ?", oldID).
+			Updates(map[string]interface{}{"migration_id": newID, "version": m.Version, "checksum": m.Checksum()})
+		if result.Error != nil {
+			return fmt.Errorf("failed to upgrade legacy migration record %s: %w", oldID, result.Error)
+		}
+		if result.RowsAffected > 0 {
+			logger.Info("旧版迁移记录已更新", zap.String("old_id", oldID), zap.String("migration_id", newID))
+		}
+	}
+	return nil
+}
+
+// checkDrift 比较已执行迁移的记录校验和与当前迁移内容
+// 任一已执行的迁移内容被修改时返回 ErrChecksumMismatch；记录中没有对应迁移（如迁移文件被删除）时只记录警告
+// 没有校验和的记录（Go 迁移或旧版记录）跳过比较
+func checkDrift(records []MigrationRecord, all []Migration) error {
+	var changed []string
+	for _, record := range records {
+		m, ok := findMigration(all, record.MigrationID)
+		if !ok {
+			logger.Warn("已执行的迁移在代码中不存在", zap.String("migration_id", record.MigrationID))
+			continue
+		}
+		checksum := m.Checksum()
+		if record.Checksum == "" || checksum == "" || record.Checksum == checksum {
+			continue
+		}
+		changed = append(changed, record.MigrationID)
+		logger.Error("已执行的迁移内容被修改",
+			zap.String("migration_id", record.MigrationID),
+			zap.String("recorded_checksum", record.Checksum),
+			zap.String("current_checksum", checksum))
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("%w: %s (add a new migration instead of editing applied ones)", ErrChecksumMismatch, strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// backfillChecksums 为没有校验和的已执行 SQL 迁移记录补齐校验和，之后的修改才能被检测到
+func backfillChecksums(db *gorm.DB, records []MigrationRecord, all []Migration) error {
+	for _, record := range records {
+		if record.Checksum != "" {
+			continue
+		}
+		m, ok := findMigration(all, record.MigrationID)
+		if !ok || m.Checksum() == "" {
+			continue
+		}
+		err := db.Model(&MigrationRecord{}).
+			Where("id = ?", record.ID).
+			Updates(map[string]interface{}{"checksum": m.Checksum(), "version": m.Version}).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill checksum for %s: %w", record.MigrationID, err)
+		}
+		logger.Info("已补齐迁移校验和", zap.String("migration_id", record.MigrationID))
+	}
+	return nil
+}
+
+// VerifyChecksums 检查已执行的迁移是否被修改
+func VerifyChecksums(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&MigrationRecord{}) {
+		return nil
+	}
+	all, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	var records []MigrationRecord
+	if err := db.Find(&records).Error; err != nil {
+		return fmt.Errorf("failed to get migration records: %w", err)
+	}
+	return checkDrift(records, all)
+}
 
 // RollbackMigration 回滚指定的迁移
 // - 按照 migrationID 找到对应迁移
-// - 执行 Down 回滚逻辑
-// - 从 migration_records 表中删除迁移记录
+// - 在同一个事务中执行 Down 回滚逻辑并删除 migration_records 中的记录
 // - 输出日志
 func RollbackMigration(db *gorm.DB, migrationID string) error {
-	for _, migration := range migrations {
-		if migration.ID == migrationID {
-			// 执行 Down 回滚
-			if err := migration.Down(db); err != nil {
-				return fmt.Errorf("rollback migration %s failed: %w", migrationID, err)
-			}
+	all, err := Migrations(db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	m, ok := findMigration(all, migrationID)
+	if !ok {
+		return fmt.Errorf("migration %s not found", migrationID)
+	}
+	return withMigrationLock(db, func() error {
+		return rollback(db, m)
+	})
+}
 
-			// 删除迁移记录
-			if err := db.Where("migration_id = ?", migrationID).Delete(&MigrationRecord{}).Error; err != nil {
-				return fmt.Errorf("failed to remove migration record %s: %w", migrationID, err)
-			}
+// rollback 在一个事务中执行迁移的 Down 逻辑并删除执行记录
+func rollback(db *gorm.DB, m Migration) error {
+	if m.Down == nil {
+		return fmt.Errorf("migration %s is irreversible: no down migration", m.ID)
+	}
 
-			// 记录日志
-			logger.Info("迁移回滚成功", zap.String("migration_id", migrationID))
-			return nil
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := m.Down(tx); err != nil {
+			return fmt.Errorf("rollback migration %s failed: %w", m.ID, err)
+		}
+		ids := []string{m.ID}
+		for oldID, newID := range legacyMigrationIDs {
+			if newID == m.ID {
+				ids = append(ids, oldID)
+			}
+		}
+		if err := tx.Where("migration_id IN ?", ids).Delete(&MigrationRecord{}).Error; err != nil {
+			return fmt.Errorf("failed to remove migration record %s: %w", m.ID, err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	// 如果找不到对应 ID
-	return fmt.Errorf("migration %s not found", migrationID)
-}
 
+	logger.Info("迁移回滚成功", zap.String("migration_id", m.ID))
+	return nil
+}
 
 // GetMigrationStatus 获取所有迁移的执行状态
 // 逻辑：
 // 1. 检查 migration_records 表是否存在：
-//    - 如果不存在：认为是开发模式，尝试用实际表的存在情况来判断
-//    - 如果存在：从 migration_records 读取已执行的迁移
-// 2. 返回每个迁移的状态，包括是否执行过、执行时间
+//    - 如果不存在：认为是开发模式（AutoMigrate），所有模型的表都存在时视为全部迁移已执行
+//    - 如果存在：从 migration_records 读取已执行的迁移（旧版 ID 按映射识别）
+// 2. 返回每个迁移的状态，包括是否执行过、执行时间、内容是否被修改
 func GetMigrationStatus(db *gorm.DB) ([]MigrationStatus, error) {
+	all, records, err := readMigrationState(db)
+	if err != nil {
+		return nil, err
+	}
+
 	// 检查 migration_records 表是否存在
 	if !db.Migrator().HasTable(&MigrationRecord{}) {
-		// 表不存在，使用实际表情况来推测状态
+		// 表不存在，AutoMigrate 一次创建所有模型的表，无法区分单个迁移，按整体判断
+		autoMigrated := true
+		for _, m := range schemaModels() {
+			if !db.Migrator().HasTable(m) {
+				autoMigrated = false
+				break
+			}
+		}
+
 		var status []MigrationStatus
-		for _, migration := range migrations {
+		for _, migration := range all {
 			s := MigrationStatus{
 				ID:       migration.ID,
-				Executed: false,
+				Version:  migration.Version,
+				Executed: autoMigrated,
 			}
-
-			// 特殊处理：001_create_users_table
-			if migration.ID == "001_create_users_table" {
-				// 判断 users 表是否存在
-				s.Executed = db.Migrator().HasTable("users")
-				if s.Executed {
-					// 没有 migration_records 表，只能标记为自动迁移
-					s.ExecutedAt = "Auto-migrated (development mode)"
-				}
+			if autoMigrated {
+				// 没有 migration_records 表，只能标记为自动迁移
+				s.Note = "Auto-migrated (development mode)"
 			}
-
 			status = append(status, s)
 		}
 		return status, nil
 	}
 
-	// migration_records 存在，直接读取
-	var records []MigrationRecord
-	if err := db.Find(&records).Error; err != nil {
-		return nil, fmt.Errorf("failed to get migration records: %w", err)
-	}
-
 	// 转换成 map，方便查找
 	recordMap := make(map[string]MigrationRecord)
 	for _, record := range records {
@@ -110,16 +428,21 @@ func GetMigrationStatus(db *gorm.DB) ([]MigrationStatus, error) {
 
 	// 遍历所有迁移，生成状态
 	var status []MigrationStatus
-	for _, migration := range migrations {
+	for _, migration := range all {
 		s := MigrationStatus{
-			ID:       migration.ID,
-			Executed: false,
+			ID:      migration.ID,
+			Version: migration.Version,
 		}
 
 		// 如果有记录，则迁移已执行
 		if record, exists := recordMap[migration.ID]; exists {
+			executedAt := record.ExecutedAt
 			s.Executed = true
-			s.ExecutedAt = record.ExecutedAt
+			s.ExecutedAt = &executedAt
+			s.Modified = record.Checksum != "" && migration.Checksum() != "" && record.Checksum != migration.Checksum()
+			if s.Modified {
+				s.Note = "checksum mismatch"
+			}
 		}
 
 		status = append(status, s)
@@ -128,13 +451,18 @@ func GetMigrationStatus(db *gorm.DB) ([]MigrationStatus, error) {
 	return status, nil
 }
 
-
 // MigrationStatus 表示迁移状态
 // - ID: 迁移 ID
+// - Version: 版本号
 // - Executed: 是否已执行
-// - ExecutedAt: 执行时间（可能是时间戳、字符串说明）
+// - ExecutedAt: 执行时间，未执行或无法确定时为 nil
+// - Modified: 已执行的迁移内容是否被修改
+// - Note: 补充说明
 type MigrationStatus struct {
-	ID         string
-	Executed   bool
-	ExecutedAt interface{}
+	ID         string     `json:"id"`
+	Version    int64      `json:"version"`
+	Executed   bool       `json:"executed"`
+	ExecutedAt *time.Time `json:"executed_at,omitempty"`
+	Modified   bool       `json:"modified"`
+	Note       string     `json:"note,omitempty"`
 }
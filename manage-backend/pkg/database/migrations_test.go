@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBuildMigrationsOrdersSQLAndGoMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id int);")},
+		"000001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"000003_add_index.up.sql":      {Data: []byte("CREATE INDEX idx ON users(id);")},
+		"README.md":                    {Data: []byte("ignored")},
+	}
+	goMigrations := []Migration{{
+		ID: "000002_backfill",
+		Up: func(*gorm.DB) error { return nil },
+	}}
+
+	all, err := buildMigrations(goMigrations, fsys, "postgres")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	assert.Equal(t, "000001_create_users", all[0].ID)
+	assert.Equal(t, int64(1), all[0].Version)
+	assert.Equal(t, "DROP TABLE users;", all[0].DownSQL)
+	assert.NotNil(t, all[0].Down)
+	assert.Len(t, all[0].Checksum(), 64)
+
+	assert.Equal(t, "000002_backfill", all[1].ID)
+	assert.Equal(t, int64(2), all[1].Version)
+	assert.Empty(t, all[1].Checksum())
+
+	assert.Equal(t, "000003_add_index", all[2].ID)
+	assert.Nil(t, all[2].Down, "missing down file means irreversible")
+}
+
+func TestBuildMigrationsRejectsInvalidInput(t *testing.T) {
+	_, err := buildMigrations(nil, fstest.MapFS{
+		"000001_a.up.sql": {Data: []byte("SELECT 1;")},
+		"000001_b.up.sql": {Data: []byte("SELECT 2;")},
+	}, "postgres")
+	assert.ErrorContains(t, err, "duplicate migration version 1")
+
+	_, err = buildMigrations(nil, fstest.MapFS{
+		"000001_a.down.sql": {Data: []byte("SELECT 1;")},
+	}, "postgres")
+	assert.ErrorContains(t, err, "has no up file")
+
+	_, err = buildMigrations([]Migration{{ID: "backfill"}}, fstest.MapFS{}, "postgres")
+	assert.ErrorContains(t, err, "numeric version")
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	all, err := Migrations("postgres")
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+
+	m, ok := findMigration(all, legacyMigrationIDs["001_create_users_table"])
+	require.True(t, ok)
+	assert.Contains(t, m.UpSQL, "CREATE TABLE IF NOT EXISTS users")
+	assert.NotNil(t, m.Down)
+}
+
+func TestCheckDrift(t *testing.T) {
+	silenceLogger(t)
+
+	all, err := buildMigrations(nil, fstest.MapFS{
+		"000001_create_users.up.sql": {Data: []byte("CREATE TABLE users (id int);")},
+		"000002_add_index.up.sql":    {Data: []byte("CREATE INDEX idx ON users(id);")},
+	}, "postgres")
+	require.NoError(t, err)
+
+	records := []MigrationRecord{
+		{MigrationID: "000001_create_users", Checksum: all[0].Checksum()},
+		{MigrationID: "000002_add_index"},         // 旧版记录没有校验和
+		{MigrationID: "000000_removed_migration"}, // 代码中已不存在
+	}
+	assert.NoError(t, checkDrift(records, all))
+
+	records[0].Checksum = "0000"
+	err = checkDrift(records, all)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.ErrorContains(t, err, "000001_create_users")
+}
+
+func TestPendingMigrations(t *testing.T) {
+	all, err := buildMigrations(nil, fstest.MapFS{
+		"000001_a.up.sql": {Data: []byte("SELECT 1;")},
+		"000002_b.up.sql": {Data: []byte("SELECT 2;")},
+		"000003_c.up.sql": {Data: []byte("SELECT 3;")},
+	}, "postgres")
+	require.NoError(t, err)
+	records := []MigrationRecord{{MigrationID: "000001_a"}}
+
+	pending, err := pendingMigrations(all, records, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002_b", "000003_c"}, migrationIDs(pending))
+
+	pending, err = pendingMigrations(all, records, "000002_b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002_b"}, migrationIDs(pending))
+
+	_, err = pendingMigrations(all, records, "000009_missing")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestCreateMigrationFiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+
+	upPath, downPath, err := CreateMigrationFiles(dir, "Add_Roles_Table", now)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "20261018093000_add_roles_table.up.sql"), upPath)
+	assert.Equal(t, filepath.Join(dir, "20261018093000_add_roles_table.down.sql"), downPath)
+
+	all, err := buildMigrations(nil, os.DirFS(dir), "postgres")
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, int64(20261018093000), all[0].Version)
+
+	_, _, err = CreateMigrationFiles(dir, "add_roles_table", now)
+	assert.Error(t, err, "existing files must not be overwritten")
+	_, _, err = CreateMigrationFiles(dir, "add roles", now)
+	assert.ErrorContains(t, err, "invalid migration name")
+}
+
+func migrationIDs(ms []Migration) []string {
+	ids := make([]string, 0, len(ms))
+	for _, m := range ms {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestCheckMigrationsAfterAutoMigrate(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	ctx := context.Background()
+
+	// 表尚未创建时所有迁移都未执行
+	_, err := CheckMigrations(ctx, db)
+	assert.ErrorContains(t, err, "pending migrations")
+
+	require.NoError(t, autoMigrate(db, &config.Config{}))
+	latest, err := CheckMigrations(ctx, db)
+	require.NoError(t, err)
+	assert.NotEmpty(t, latest)
+
+	status, err := GetMigrationStatus(db)
+	require.NoError(t, err)
+	for _, s := range status {
+		assert.True(t, s.Executed, s.ID)
+		assert.Equal(t, "Auto-migrated (development mode)", s.Note)
+	}
+}
@@ -0,0 +1,331 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+// replicaPingTimeout 单个副本健康检查的超时时间
+const replicaPingTimeout = 3 * time.Second
+
+// ReplicaSet 只读副本集合
+// 读操作（Query/Row 以及 SELECT 开头的 Raw）轮询健康的副本，没有健康副本时回退到主库；
+// 写操作、事务内的操作和 FOR UPDATE 查询始终使用主库
+type ReplicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	interval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// replica 单个副本连接，实现 gorm.ConnPool
+// 不暴露 Ping 方法，注册时 GORM 不会主动连接，副本启动时不可用也不影响服务启动
+type replica struct {
+	db      *sql.DB
+	addr    string
+	healthy atomic.Bool
+}
+
+func (r *replica) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.db.PrepareContext(ctx, query)
+}
+
+func (r *replica) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.db.ExecContext(ctx, query, args...)
+}
+
+func (r *replica) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.db.QueryContext(ctx, query, args...)
+}
+
+func (r *replica) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.db.QueryRowContext(ctx, query, args...)
+}
+
+var _ gorm.ConnPool = (*replica)(nil)
+
+// poolDialector 使用已有连接池的 Dialector，SQL 构建沿用主库的 Dialector
+// dbresolver 只取它创建的连接池，不会执行任何初始化查询
+type poolDialector struct {
+	gorm.Dialector
+	pool gorm.ConnPool
+}
+
+func (d poolDialector) Initialize(db *gorm.DB) error {
+	db.ConnPool = d.pool
+	return nil
+}
+
+// UseReplicas 为 db 注册只读副本路由，未配置副本时返回 nil
+// 需在数据库迁移之后调用，返回的 ReplicaSet 在后台定期检查副本健康状态，关闭服务时调用 Close
+func UseReplicas(db *gorm.DB, cfg config.Database) (*ReplicaSet, error) {
+	if len(cfg.Replicas) == 0 {
+		return nil, nil
+	}
+	cfg = cfg.WithDefaults()
+
+	rs := &ReplicaSet{
+		interval: cfg.ReplicaCheckInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for i, rc := range cfg.Replicas {
+		r, err := openReplica(cfg.Replica(rc))
+		if err != nil {
+			rs.closeReplicas()
+			return nil, fmt.Errorf("failed to open replica %d: %w", i, err)
+		}
+		rs.replicas = append(rs.replicas, r)
+	}
+
+	// 主库作为最后一个候选，保证副本数量为 1 时也会经过 Resolve（dbresolver 只有一个候选时不调用策略）
+	primary := db.Config.ConnPool
+	if prepared, ok := primary.(*gorm.PreparedStmtDB); ok {
+		primary = prepared.ConnPool
+	}
+	dialectors := make([]gorm.Dialector, 0, len(rs.replicas)+1)
+	for _, r := range rs.replicas {
+		dialectors = append(dialectors, poolDialector{Dialector: db.Dialector, pool: r})
+	}
+	dialectors = append(dialectors, poolDialector{Dialector: db.Dialector, pool: primary})
+
+	if err := db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy:   rs,
+	})); err != nil {
+		rs.closeReplicas()
+		return nil, fmt.Errorf("failed to register replicas: %w", err)
+	}
+	if err := registerStickyCallbacks(db); err != nil {
+		rs.closeReplicas()
+		return nil, err
+	}
+
+	rs.checkAll(context.Background())
+	go rs.run()
+
+	logger.Info("已启用数据库只读副本", zap.Int("replicas", len(rs.replicas)), zap.Duration("check_interval", rs.interval))
+	return rs, nil
+}
+
+// openReplica 创建副本连接池，不主动连接
+func openReplica(cfg config.Database) (*replica, error) {
+	dialector, err := Dialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// MySQL 初始化时会查询服务端版本，副本使用主库的 Dialector 构建 SQL，不需要查询
+	// （SQLite 初始化时同样会查询版本，但只访问本地文件）
+	if d, ok := dialector.(*mysql.Dialector); ok {
+		d.Config.SkipInitializeWithVersion = true
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := configurePool(db, cfg); err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return &replica{db: sqlDB, addr: replicaAddr(cfg)}, nil
+}
+
+// replicaAddr 用于日志和健康检查中标识副本
+func replicaAddr(cfg config.Database) string {
+	if cfg.Driver == config.DatabaseDriverSQLite {
+		return cfg.Name
+	}
+	return cfg.Host + ":" + cfg.Port
+}
+
+// Resolve 实现 dbresolver.Policy：轮询健康的副本，全部不可用时返回主库
+func (rs *ReplicaSet) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	n := uint64(len(rs.replicas))
+	start := rs.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		r := rs.replicas[(start+i)%n]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return pools[len(pools)-1]
+}
+
+var _ dbresolver.Policy = (*ReplicaSet)(nil)
+
+// run 定期检查副本健康状态
+func (rs *ReplicaSet) run() {
+	defer close(rs.done)
+
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rs.stop:
+			return
+		case <-ticker.C:
+			rs.checkAll(context.Background())
+		}
+	}
+}
+
+// checkAll 检查所有副本并更新健康状态，返回健康副本数量和不可用副本的错误
+func (rs *ReplicaSet) checkAll(ctx context.Context) (int, []string) {
+	var healthy int
+	var failures []string
+	for _, r := range rs.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		err := r.db.PingContext(pingCtx)
+		cancel()
+
+		was := r.healthy.Swap(err == nil)
+		switch {
+		case err == nil:
+			healthy++
+			if !was {
+				logger.Info("数据库副本可用", zap.String("replica", r.addr))
+			}
+		default:
+			failures = append(failures, fmt.Sprintf("%s: %v", r.addr, err))
+			if was {
+				logger.Warn("数据库副本不可用，读操作将不再路由到该副本", zap.String("replica", r.addr), zap.Error(err))
+			}
+		}
+	}
+	return healthy, failures
+}
+
+// Check 立即检查所有副本，用于就绪检查，返回健康副本数量
+// 有副本不可用时返回错误（读操作仍可回退到主库）
+func (rs *ReplicaSet) Check(ctx context.Context) (string, error) {
+	healthy, failures := rs.checkAll(ctx)
+	status := fmt.Sprintf("%d/%d healthy", healthy, len(rs.replicas))
+	if len(failures) > 0 {
+		return status, fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return status, nil
+}
+
+// Close 停止健康检查并关闭副本连接
+func (rs *ReplicaSet) Close(ctx context.Context) error {
+	rs.stopOnce.Do(func() { close(rs.stop) })
+	select {
+	case <-rs.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return rs.closeReplicas()
+}
+
+func (rs *ReplicaSet) closeReplicas() error {
+	var firstErr error
+	for _, r := range rs.replicas {
+		if err := r.db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// stickyKey 写后读主库的上下文标记
+type stickyKey struct{}
+
+// stickyState 记录同一个上下文（通常是一个请求）中是否已经执行过写操作
+type stickyState struct {
+	written atomic.Bool
+}
+
+// WithStickyPrimary 返回带写后读主库标记的上下文
+// 使用该上下文执行写操作后，后续的读操作都使用主库，避免读到副本上尚未同步的旧数据
+func WithStickyPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stickyKey{}, &stickyState{})
+}
+
+// WithPrimary 返回读操作始终使用主库的上下文
+// 用于读取后会被长期保存的数据（如回填缓存），避免把副本上尚未同步的旧数据缓存下来；未启用副本时没有影响
+func WithPrimary(ctx context.Context) context.Context {
+	state := &stickyState{}
+	state.written.Store(true)
+	return context.WithValue(ctx, stickyKey{}, state)
+}
+
+// stickyStateFrom 获取上下文中的写后读标记
+func stickyStateFrom(ctx context.Context) *stickyState {
+	if ctx == nil {
+		return nil
+	}
+	state, _ := ctx.Value(stickyKey{}).(*stickyState)
+	return state
+}
+
+// registerStickyCallbacks 注册写后读主库的回调：写操作成功后标记上下文，之后的读操作切换到主库
+func registerStickyCallbacks(db *gorm.DB) error {
+	const (
+		markName  = "gms:sticky_primary:mark"
+		routeName = "gms:sticky_primary:route"
+	)
+
+	markWritten := func(db *gorm.DB) {
+		if db.Error != nil {
+			return
+		}
+		if state := stickyStateFrom(db.Statement.Context); state != nil {
+			state.written.Store(true)
+		}
+	}
+	markRawWrite := func(db *gorm.DB) {
+		if !isSelect(db.Statement.SQL.String()) {
+			markWritten(db)
+		}
+	}
+	route := func(db *gorm.DB) {
+		if state := stickyStateFrom(db.Statement.Context); state != nil && state.written.Load() {
+			dbresolver.Write.ModifyStatement(db.Statement)
+		}
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().After("*").Register(markName, markWritten),
+		cb.Update().After("*").Register(markName, markWritten),
+		cb.Delete().After("*").Register(markName, markWritten),
+		cb.Raw().After("*").Register(markName, markRawWrite),
+		cb.Query().Before("gorm:query").Register(routeName, route),
+		cb.Row().Before("gorm:row").Register(routeName, route),
+		cb.Raw().Before("gorm:raw").Register(routeName, route),
+	} {
+		if err != nil {
+			return fmt.Errorf("failed to register sticky primary callbacks: %w", err)
+		}
+	}
+	return nil
+}
+
+// isSelect 判断原生 SQL 是否为只读查询
+func isSelect(sql string) bool {
+	sql = strings.TrimSpace(sql)
+	return len(sql) >= 6 && strings.EqualFold(sql[:6], "select")
+}
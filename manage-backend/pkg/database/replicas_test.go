@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newReplicaTestDB 创建主库和副本两个 SQLite 文件，各写入一个只在该库存在的用户，用于判断查询路由到哪个库
+func newReplicaTestDB(t *testing.T) (*gorm.DB, *ReplicaSet) {
+	t.Helper()
+	silenceLogger(t)
+
+	dir := t.TempDir()
+	cfg := config.Database{Driver: config.DatabaseDriverSQLite, Name: filepath.Join(dir, "primary.db")}
+	replicaPath := filepath.Join(dir, "replica.db")
+	replicaDB, err := Init(config.Database{Driver: config.DatabaseDriverSQLite, Name: replicaPath})
+	require.NoError(t, err)
+	require.NoError(t, replicaDB.AutoMigrate(schemaModels()...))
+	require.NoError(t, replicaDB.Create(&model.User{Username: "on_replica", Email: "r@example.com", Password: "x", Role: "user", Status: "active"}).Error)
+	sqlDB, _ := replicaDB.DB()
+	sqlDB.Close()
+	cfg.Replicas = []config.DatabaseReplica{{Name: replicaPath}}
+
+	db, err := Init(cfg)
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(schemaModels()...))
+	require.NoError(t, db.Create(&model.User{Username: "on_primary", Email: "p@example.com", Password: "x", Role: "user", Status: "active"}).Error)
+
+	rs, err := UseReplicas(db, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, rs)
+	t.Cleanup(func() {
+		rs.Close(context.Background())
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	return db, rs
+}
+
+func usernames(t *testing.T, db *gorm.DB) []string {
+	var names []string
+	require.NoError(t, db.Model(&model.User{}).Order("id").Pluck("username", &names).Error)
+	return names
+}
+
+func TestUseReplicasWithoutReplicas(t *testing.T) {
+	rs, err := UseReplicas(nil, config.Database{})
+	require.NoError(t, err)
+	assert.Nil(t, rs)
+}
+
+func TestReplicaRouting(t *testing.T) {
+	db, rs := newReplicaTestDB(t)
+	ctx := context.Background()
+
+	// 读操作使用副本，写操作使用主库
+	assert.Equal(t, []string{"on_replica"}, usernames(t, db.WithContext(ctx)))
+	require.NoError(t, db.WithContext(ctx).Create(&model.User{Username: "created", Email: "c@example.com", Password: "x", Role: "user", Status: "active"}).Error)
+	assert.Equal(t, []string{"on_replica"}, usernames(t, db.WithContext(ctx)))
+
+	var count int64
+	require.NoError(t, db.WithContext(ctx).Model(&model.User{}).Where("username = ?", "created").Count(&count).Error)
+	assert.Zero(t, count)
+
+	// 就绪检查使用主库：副本上有空的迁移记录表（迁移未执行），主库为 AutoMigrate
+	replicaDB, err := Init(config.Database{Driver: config.DatabaseDriverSQLite, Name: rs.replicas[0].addr})
+	require.NoError(t, err)
+	require.NoError(t, replicaDB.AutoMigrate(&MigrationRecord{}))
+	replicaSQL, _ := replicaDB.DB()
+	replicaSQL.Close()
+	_, err = CheckMigrations(ctx, db)
+	assert.NoError(t, err)
+
+	status, err := rs.Check(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1/1 healthy", status)
+}
+
+func TestReplicaStickyPrimaryAfterWrite(t *testing.T) {
+	db, _ := newReplicaTestDB(t)
+	ctx := WithStickyPrimary(context.Background())
+
+	assert.Equal(t, []string{"on_replica"}, usernames(t, db.WithContext(ctx)))
+	require.NoError(t, db.WithContext(ctx).Create(&model.User{Username: "created", Email: "c@example.com", Password: "x", Role: "user", Status: "active"}).Error)
+	assert.Equal(t, []string{"on_primary", "created"}, usernames(t, db.WithContext(ctx)))
+
+	var user model.User
+	require.NoError(t, db.WithContext(ctx).Where("username = ?", "created").First(&user).Error)
+
+	// 其他请求不受影响
+	assert.Equal(t, []string{"on_replica"}, usernames(t, db.WithContext(WithStickyPrimary(context.Background()))))
+}
+
+func TestReplicaWithPrimary(t *testing.T) {
+	db, _ := newReplicaTestDB(t)
+
+	// 未写入过也使用主库
+	assert.Equal(t, []string{"on_primary"}, usernames(t, db.WithContext(WithPrimary(context.Background()))))
+	var user model.User
+	require.NoError(t, db.WithContext(WithPrimary(context.Background())).Where("username = ?", "on_primary").First(&user).Error)
+}
+
+func TestReplicaUnhealthyFallsBackToPrimary(t *testing.T) {
+	db, rs := newReplicaTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, rs.replicas[0].db.Close())
+
+	status, err := rs.Check(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, "0/1 healthy", status)
+	assert.Equal(t, []string{"on_primary"}, usernames(t, db.WithContext(ctx)))
+}
@@ -1,49 +1,131 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
-	"gorm.io/gorm"
-	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
-// ResetDatabase 重置数据库（删除所有表）
+// ResetConfirmToken 生产环境重置数据库时需要输入的确认口令，形如 reset-<数据库名>
+func ResetConfirmToken(cfg config.Database) string {
+	return "reset-" + cfg.Name
+}
+
+// CheckResetAllowed 检查是否允许重置数据库
+// 生产环境必须提供与 ResetConfirmToken 一致的确认口令，其他环境不检查
+// 错误信息只说明口令格式，不包含口令本身，避免从日志中直接复制
+func CheckResetAllowed(env string, cfg config.Database, token string) error {
+	if env != "production" {
+		return nil
+	}
+	if token != ResetConfirmToken(cfg) {
+		return errors.New("refusing to reset production database: a confirmation token of the form reset-<database name> is required")
+	}
+	return nil
+}
+
+// ListTables 列出当前模式（search_path 中的第一个模式）下的所有表
+// SQLite 的内部表（如 sqlite_sequence）不能删除，不包含在结果中
+func ListTables(db *gorm.DB) ([]string, error) {
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	if db.Dialector.Name() != "sqlite" {
+		return tables, nil
+	}
+	result := tables[:0]
+	for _, table := range tables {
+		if !strings.HasPrefix(table, "sqlite_") {
+			result = append(result, table)
+		}
+	}
+	return result, nil
+}
+
+// ResetDatabase 重置数据库（删除当前模式下的所有表）
+// 表名从数据库中查询，新增的表无需在这里登记；所有表在一个事务中删除
 func ResetDatabase(db *gorm.DB) error {
 	logger.Info("开始重置数据库")
-	
-	// 获取所有表名
-	tables := []string{
-		"migration_records",
-		"users",
-		// 在这里添加其他表名
-	}
-	
-	// 删除所有表
-	for _, table := range tables {
-		if db.Migrator().HasTable(table) {
-			if err := db.Migrator().DropTable(table); err != nil {
-				logger.Warn("删除表失败", zap.String("table", table), zap.Error(err))
-			} else {
-				logger.Info("删除表成功", zap.String("table", table))
+
+	tables, err := ListTables(db)
+	if err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		logger.Info("当前模式下没有数据表")
+		return nil
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			if err := tx.Migrator().DropTable(table); err != nil {
+				return fmt.Errorf("failed to drop table %s: %w", table, err)
 			}
+			logger.Info("删除表成功", zap.String("table", table))
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("数据库重置完成", zap.Int("tables", len(tables)))
+	return nil
+}
+
+// BackupDatabase 使用 pg_dump 将数据库（只包含配置的模式）备份到本地文件
+// 文件扩展名为 .dump 时使用自定义格式（可用 pg_restore 恢复），否则输出纯 SQL
+func BackupDatabase(ctx context.Context, cfg config.Database, path string) error {
+	if cfg.Driver != "" && cfg.Driver != config.DatabaseDriverPostgres {
+		return fmt.Errorf("pg_dump backup is not supported for driver %q, use cmd/backup instead", cfg.Driver)
+	}
+	if _, err := exec.LookPath("pg_dump"); err != nil {
+		return fmt.Errorf("pg_dump not found in PATH: %w", err)
 	}
-	
-	// 或者使用模型来删除表（更安全的方式）
-	models := []interface{}{
-		&MigrationRecord{},
-		&model.User{},
-		// 在这里添加其他模型
-	}
-	
-	for _, model := range models {
-		if err := db.Migrator().DropTable(model); err != nil {
-			logger.Warn("删除模型表失败", zap.String("model", fmt.Sprintf("%T", model)), zap.Error(err))
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create backup dir: %w", err)
 		}
 	}
-	
-	logger.Info("数据库重置完成")
+
+	args := []string{
+		"--host", cfg.Host,
+		"--port", cfg.Port,
+		"--username", cfg.User,
+		"--dbname", cfg.Name,
+		"--file", path,
+		"--no-owner",
+	}
+	if cfg.Schema != "" {
+		args = append(args, "--schema", cfg.Schema)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".dump") {
+		args = append(args, "--format", "custom")
+	}
+
+	cmd := exec.CommandContext(ctx, "pg_dump", args...)
+	// 密码通过环境变量传入，避免出现在进程参数中
+	cmd.Env = append(os.Environ(),
+		"PGPASSWORD="+cfg.Password,
+		"PGSSLMODE="+cfg.SSLMode,
+		"PGSSLROOTCERT="+cfg.SSLRootCert,
+		"PGSSLCERT="+cfg.SSLCert,
+		"PGSSLKEY="+cfg.SSLKey,
+		"PGAPPNAME="+cfg.ApplicationName)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("pg_dump failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	logger.Info("数据库备份完成", zap.String("path", path))
 	return nil
-}
\ No newline at end of file
+}
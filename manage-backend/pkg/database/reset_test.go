@@ -0,0 +1,46 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckResetAllowed(t *testing.T) {
+	cfg := config.Database{Name: "manage_prod"}
+
+	assert.NoError(t, CheckResetAllowed("development", cfg, ""))
+	assert.Error(t, CheckResetAllowed("production", cfg, ""))
+	assert.Error(t, CheckResetAllowed("production", cfg, "reset-other"))
+	assert.NoError(t, CheckResetAllowed("production", cfg, "reset-manage_prod"))
+
+	// 错误信息不回显确认口令
+	err := CheckResetAllowed("production", cfg, "")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), ResetConfirmToken(cfg))
+	assert.NotContains(t, err.Error(), cfg.Name)
+}
+
+func TestListTablesAndResetDatabase(t *testing.T) {
+	db := newSQLiteTestDB(t)
+
+	tables, err := ListTables(db)
+	require.NoError(t, err)
+	assert.Empty(t, tables)
+	require.NoError(t, ResetDatabase(db), "empty database")
+
+	require.NoError(t, db.AutoMigrate(schemaModels()...))
+	tables, err = ListTables(db)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tables)
+	for _, m := range schemaModels() {
+		assert.True(t, db.Migrator().HasTable(m))
+	}
+
+	require.NoError(t, ResetDatabase(db))
+	tables, err = ListTables(db)
+	require.NoError(t, err)
+	assert.Empty(t, tables)
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// migrationNamePattern 新建迁移的名称格式
+var migrationNamePattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// CreateMigrationFiles 在 dir 下创建一对空的 up/down SQL 迁移文件，版本号为当前时间（YYYYMMDDHHMMSS）
+// 返回创建的 up 和 down 文件路径
+func CreateMigrationFiles(dir, name string, now time.Time) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !migrationNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("invalid migration name %q: use lowercase letters, digits and underscores", name)
+	}
+
+	id := now.UTC().Format("20060102150405") + "_" + name
+	upPath := filepath.Join(dir, id+".up.sql")
+	downPath := filepath.Join(dir, id+".down.sql")
+
+	files := []struct {
+		path    string
+		content string
+	}{
+		{upPath, fmt.Sprintf("-- %s: 执行迁移\n-- 每个迁移在单独的事务中执行，执行后不要再修改本文件（校验和会变化）\n\n", id)},
+		{downPath, fmt.Sprintf("-- %s: 回滚迁移\n\n", id)},
+	}
+	for _, f := range files {
+		// O_EXCL：同名文件已存在时报错，不覆盖
+		file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create migration file: %w", err)
+		}
+		_, err = file.WriteString(f.content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to write migration file %s: %w", f.path, err)
+		}
+	}
+	return upPath, downPath, nil
+}
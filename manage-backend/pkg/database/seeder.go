@@ -1,184 +1,323 @@
 package database
 
 import (
-	"gorm.io/gorm"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// SeedDatabase 根据环境种子数据库
+// fakeUserBatchSize 批量插入模拟用户的批大小
+const fakeUserBatchSize = 1000
+
+// SeedOptions 种子数据选项
+type SeedOptions struct {
+	// 种子文件根目录，实际读取 <Dir>/<环境> 下的文件，为空时自动查找 config/seeds
+	Dir string
+	// 只处理指定类型（roles, settings, users），为空时处理全部
+	Only []string
+	// 写入前清空对应的表，生产环境禁止使用
+	Truncate bool
+
+	// 额外生成的模拟用户数量（用于压测），用户名为 loadtest_000001 形式，生产环境禁止使用
+	FakeUsers int
+	// 模拟用户的密码，为空时使用 password123
+	FakePassword string
+}
+
+// SeedDatabase 根据环境种子数据库，使用 config/seeds/<环境> 下的种子文件
 func SeedDatabase(db *gorm.DB, env string) error {
-	logger.Info("开始数据库种子数据", zap.String("environment", env))
-	
-	switch env {
-	case "development":
-		return seedDevelopmentData(db)
-	case "test":
-		return seedTestData(db)
-	case "production":
-		return seedProductionData(db)
-	default:
-		logger.Warn("未配置种子数据", zap.String("environment", env))
+	return Seed(db, env, SeedOptions{})
+}
+
+// Seed 从种子文件写入初始数据
+// 所有写入都是按唯一键的更新或插入，重复执行结果相同；种子文件中的数据在一个事务中写入
+func Seed(db *gorm.DB, env string, opts SeedOptions) error {
+	kinds, err := selectFixtureKinds(opts.Only)
+	if err != nil {
+		return err
+	}
+	if opts.Truncate && env == "production" {
+		return fmt.Errorf("truncate is not allowed in production")
+	}
+	if opts.FakeUsers > 0 && env == "production" {
+		// 模拟用户使用已知的默认密码，不能出现在生产数据库中
+		return fmt.Errorf("fake users are not allowed in production")
+	}
+
+	root := opts.Dir
+	if root == "" {
+		root = findSeedDir()
+	}
+	dir := filepath.Join(root, env)
+	fixtures, err := LoadFixtures(dir)
+	if err != nil {
+		return err
+	}
+	logger.Info("开始写入种子数据", zap.String("environment", env), zap.String("dir", dir), zap.Strings("only", kinds))
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if opts.Truncate {
+			if err := truncateFixtureTables(tx, kinds); err != nil {
+				return err
+			}
+		}
+		for _, kind := range kinds {
+			var err error
+			switch kind {
+			case FixtureRoles:
+				err = seedRoles(tx, fixtures.Roles)
+			case FixtureSettings:
+				err = seedSettings(tx, fixtures.Settings)
+			case FixtureUsers:
+				err = seedUsers(tx, fixtures.Users)
+			}
+			if err != nil {
+				return fmt.Errorf("seed %s: %w", kind, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.FakeUsers > 0 {
+		if err := seedFakeUsers(db, opts.FakeUsers, opts.FakePassword); err != nil {
+			return fmt.Errorf("seed fake users: %w", err)
+		}
+	}
+
+	logger.Info("种子数据写入完成", zap.String("environment", env))
+	return nil
+}
+
+// selectFixtureKinds 校验并按写入顺序返回要处理的种子数据类型
+func selectFixtureKinds(only []string) ([]string, error) {
+	if len(only) == 0 {
+		return fixtureKinds, nil
+	}
+
+	selected := make(map[string]bool, len(only))
+	for _, kind := range only {
+		kind = strings.TrimSpace(kind)
+		valid := false
+		for _, k := range fixtureKinds {
+			if k == kind {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown fixture kind %q, expected one of %s", kind, strings.Join(fixtureKinds, ", "))
+		}
+		selected[kind] = true
+	}
+
+	var kinds []string
+	for _, k := range fixtureKinds {
+		if selected[k] {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds, nil
+}
+
+// findSeedDir 查找种子文件根目录（与配置文件的查找路径一致）
+func findSeedDir() string {
+	for _, dir := range []string{"./config/seeds", "../config/seeds", "../../config/seeds"} {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return "./config/seeds"
+}
+
+// truncateFixtureTables 清空种子数据对应的表（包括软删除的记录）
+func truncateFixtureTables(tx *gorm.DB, kinds []string) error {
+	models := map[string]interface{}{
+		FixtureRoles:    &model.Role{},
+		FixtureSettings: &model.Setting{},
+		FixtureUsers:    &model.User{},
+	}
+	for i := len(kinds) - 1; i >= 0; i-- {
+		result := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(models[kinds[i]])
+		if result.Error != nil {
+			return fmt.Errorf("failed to truncate %s: %w", kinds[i], result.Error)
+		}
+		logger.Info("已清空数据表", zap.String("kind", kinds[i]), zap.Int64("rows", result.RowsAffected))
+	}
+	return nil
+}
+
+// seedRoles 按名称更新或插入角色
+func seedRoles(tx *gorm.DB, fixtures []RoleFixture) error {
+	if len(fixtures) == 0 {
 		return nil
 	}
+	roles := make([]model.Role, 0, len(fixtures))
+	for _, f := range fixtures {
+		roles = append(roles, model.Role{Name: f.Name, DisplayName: f.DisplayName, Description: f.Description})
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"display_name", "description", "updated_at"}),
+	}).Create(&roles).Error
+	if err != nil {
+		return err
+	}
+	logger.Info("角色种子数据已写入", zap.Int("count", len(roles)))
+	return nil
 }
 
-// seedDevelopmentData 开发环境种子数据
-func seedDevelopmentData(db *gorm.DB) error {
-	logger.Info("开始种子开发环境数据")
-	
-	// 创建默认管理员用户
-	hashedPassword, err := utils.HashPassword("admin123")
+// seedSettings 按键更新或插入系统设置
+func seedSettings(tx *gorm.DB, fixtures []SettingFixture) error {
+	if len(fixtures) == 0 {
+		return nil
+	}
+	settings := make([]model.Setting, 0, len(fixtures))
+	for _, f := range fixtures {
+		settings = append(settings, model.Setting{Key: f.Key, Value: f.Value, Description: f.Description})
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "description", "updated_at"}),
+	}).Create(&settings).Error
 	if err != nil {
 		return err
 	}
-	
-	admin := &model.User{
-		Username: "admin",
-		Email:    "admin@example.com",
-		Password: hashedPassword,
-		Role:     "admin",
-		Status:   "active",
-	}
-	
-	// 使用 FirstOrCreate 避免重复创建
-	result := db.Where("username = ?", admin.Username).FirstOrCreate(admin)
-	if result.Error != nil {
-		return result.Error
-	}
-	
-	if result.RowsAffected > 0 {
-		logger.Info("创建默认管理员用户", zap.String("username", "admin"))
-	} else {
-		logger.Info("管理员用户已存在", zap.String("username", "admin"))
-	}
-	
-	// 创建测试用户
-	testUser := &model.User{
-		Username: "testuser",
-		Email:    "test@example.com",
-		Password: hashedPassword,
-		Role:     "user",
-		Status:   "active",
-	}
-	
-	result = db.Where("username = ?", testUser.Username).FirstOrCreate(testUser)
-	if result.Error != nil {
-		return result.Error
-	}
-	
-	if result.RowsAffected > 0 {
-		logger.Info("创建测试用户", zap.String("username", "testuser"))
-	} else {
-		logger.Info("测试用户已存在", zap.String("username", "testuser"))
-	}
-	
-	logger.Info("开发环境数据种子完成")
+	logger.Info("系统设置种子数据已写入", zap.Int("count", len(settings)))
 	return nil
 }
 
-// seedTestData 测试环境种子数据
-func seedTestData(db *gorm.DB) error {
-	logger.Info("开始种子测试环境数据")
-	
-	// 创建测试管理员用户
-	hashedPassword, err := utils.HashPassword("test123")
+// seedUsers 按用户名更新或插入用户
+// 已存在的用户只更新邮箱、角色和状态（并恢复软删除），不覆盖密码
+func seedUsers(tx *gorm.DB, fixtures []UserFixture) error {
+	if len(fixtures) == 0 {
+		return nil
+	}
+
+	usernames := make([]string, 0, len(fixtures))
+	for _, f := range fixtures {
+		usernames = append(usernames, f.Username)
+	}
+	var existing []string
+	if err := tx.Unscoped().Model(&model.User{}).Where("username IN ?", usernames).Pluck("username", &existing).Error; err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		exists[name] = true
+	}
+
+	users := make([]model.User, 0, len(fixtures))
+	created := 0
+	for _, f := range fixtures {
+		user := model.User{
+			Username: f.Username,
+			Email:    f.Email,
+			Role:     f.Role,
+			Status:   f.Status,
+		}
+		if user.Role == "" {
+			user.Role = "user"
+		}
+		if user.Status == "" {
+			user.Status = "active"
+		}
+
+		if !exists[f.Username] {
+			password := os.ExpandEnv(f.Password)
+			if password == "" {
+				return fmt.Errorf("user %q: password is required for new users", f.Username)
+			}
+			hashed, err := utils.HashPassword(password)
+			if err != nil {
+				return err
+			}
+			user.Password = hashed
+			created++
+		}
+		users = append(users, user)
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "username"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "role", "status", "deleted_at", "updated_at"}),
+	}).Create(&users).Error
 	if err != nil {
 		return err
 	}
-	
-	admin := &model.User{
-		Username: "testadmin",
-		Email:    "testadmin@example.com",
-		Password: hashedPassword,
-		Role:     "admin",
-		Status:   "active",
-	}
-	
-	// 使用 FirstOrCreate 避免重复创建
-	result := db.Where("username = ?", admin.Username).FirstOrCreate(admin)
-	if result.Error != nil {
-		return result.Error
-	}
-	
-	if result.RowsAffected > 0 {
-		logger.Info("创建测试管理员用户", zap.String("username", "testadmin"))
-	} else {
-		logger.Info("测试管理员用户已存在", zap.String("username", "testadmin"))
-	}
-	
-	// 创建测试普通用户
-	testUser := &model.User{
-		Username: "testuser",
-		Email:    "testuser@example.com",
-		Password: hashedPassword,
-		Role:     "user",
-		Status:   "active",
-	}
-	
-	result = db.Where("username = ?", testUser.Username).FirstOrCreate(testUser)
-	if result.Error != nil {
-		return result.Error
-	}
-	
-	if result.RowsAffected > 0 {
-		logger.Info("创建测试用户", zap.String("username", "testuser"))
-	} else {
-		logger.Info("测试用户已存在", zap.String("username", "testuser"))
-	}
-	
-	logger.Info("测试环境数据种子完成")
+	logger.Info("用户种子数据已写入", zap.Int("created", created), zap.Int("updated", len(users)-created))
 	return nil
 }
 
-// seedProductionData 生产环境种子数据
-func seedProductionData(db *gorm.DB) error {
-	logger.Info("开始种子生产环境数据")
-	
-	// 生产环境只创建必要的初始数据
-	// 比如系统管理员账户（如果不存在的话）
-	
-	var count int64
-	db.Model(&model.User{}).Where("role = ?", "admin").Count(&count)
-	
-	if count == 0 {
-		logger.Warn("未找到管理员用户，创建默认管理员")
-		
-		hashedPassword, err := utils.HashPassword("ChangeMe123!")
-		if err != nil {
-			return err
+// seedFakeUsers 批量生成模拟用户，已存在的用户名跳过
+// 所有模拟用户使用同一个密码哈希，避免逐个计算 bcrypt
+func seedFakeUsers(db *gorm.DB, n int, password string) error {
+	if password == "" {
+		password = "password123"
+	}
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	var inserted int64
+	batch := make([]model.User, 0, fakeUserBatchSize)
+	flush := func() error {
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&batch)
+		if result.Error != nil {
+			return result.Error
 		}
-		
-		admin := &model.User{
-			Username: "admin",
-			Email:    "admin@yourdomain.com",
-			Password: hashedPassword,
-			Role:     "admin",
+		inserted += result.RowsAffected
+		batch = batch[:0]
+		return nil
+	}
+
+	for i := 1; i <= n; i++ {
+		username := fmt.Sprintf("loadtest_%06d", i)
+		batch = append(batch, model.User{
+			Username: username,
+			Email:    username + "@loadtest.local",
+			Password: hashed,
+			Role:     "user",
 			Status:   "active",
+		})
+		if len(batch) == fakeUserBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
 		}
-		
-		if err := db.Create(admin).Error; err != nil {
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
 			return err
 		}
-		
-		logger.Warn("创建默认管理员用户 - 请立即更改密码！", zap.String("username", "admin"))
 	}
-	
-	logger.Info("生产环境数据种子完成")
+
+	logger.Info("模拟用户已生成", zap.Int("requested", n), zap.Int64("inserted", inserted))
 	return nil
 }
 
 // CleanDatabase 清理数据库（主要用于测试）
 func CleanDatabase(db *gorm.DB) error {
 	logger.Info("开始清理数据库")
-	
+
 	// 删除所有用户数据
 	if err := db.Exec("DELETE FROM users").Error; err != nil {
 		return err
 	}
-	
+
 	logger.Info("数据库清理成功")
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// 结构差异类型
+const (
+	SchemaIssueMissingTable  = "missing_table"
+	SchemaIssueMissingColumn = "missing_column"
+	SchemaIssueMissingIndex  = "missing_index"
+	SchemaIssueNullable      = "nullable_mismatch"
+)
+
+// SchemaIssue 数据库实际结构与模型定义不一致的地方
+type SchemaIssue struct {
+	Table  string `json:"table"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func (i SchemaIssue) String() string {
+	s := fmt.Sprintf("%s: %s", i.Table, i.Kind)
+	if i.Name != "" {
+		s += " " + i.Name
+	}
+	if i.Detail != "" {
+		s += " (" + i.Detail + ")"
+	}
+	return s
+}
+
+// VerifySchema 比较数据库实际结构与模型定义，返回缺失的表、列、索引以及 NOT NULL 约束不一致的列
+// 只检查模型要求存在的结构，数据库中多出的表和列不视为问题
+func VerifySchema(db *gorm.DB) ([]SchemaIssue, error) {
+	var issues []SchemaIssue
+	migrator := db.Migrator()
+
+	for _, m := range schemaModels() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", m, err)
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(m) {
+			issues = append(issues, SchemaIssue{Table: table, Kind: SchemaIssueMissingTable})
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get columns of %s: %w", table, err)
+		}
+		columns := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, ct := range columnTypes {
+			columns[ct.Name()] = ct
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			ct, ok := columns[field.DBName]
+			if !ok {
+				issues = append(issues, SchemaIssue{Table: table, Kind: SchemaIssueMissingColumn, Name: field.DBName})
+				continue
+			}
+			if !field.NotNull || field.PrimaryKey {
+				continue
+			}
+			if nullable, ok := ct.Nullable(); ok && nullable {
+				issues = append(issues, SchemaIssue{
+					Table:  table,
+					Kind:   SchemaIssueNullable,
+					Name:   field.DBName,
+					Detail: "model requires NOT NULL",
+				})
+			}
+		}
+
+		for _, idx := range stmt.Schema.ParseIndexes() {
+			if !migrator.HasIndex(m, idx.Name) {
+				issues = append(issues, SchemaIssue{Table: table, Kind: SchemaIssueMissingIndex, Name: idx.Name})
+			}
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,194 @@
+// Package export 提供逐行读写的表格文件（CSV、XLSX），用于大量数据的导出和批量导入，避免一次性加载到内存
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ErrUnsupportedFormat 不支持的导出格式
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Format 导出格式
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// TimeLayout 时间列的输出格式
+const TimeLayout = "2006-01-02 15:04:05"
+
+// ParseFormat 解析导出格式，空字符串为 CSV
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "":
+		return FormatCSV, nil
+	case FormatCSV, FormatXLSX:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, s)
+	}
+}
+
+// ContentType 返回 HTTP Content-Type
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension 返回文件扩展名（不含点）
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// Writer 逐行写入表格，第一行通常为表头，Close 后输出才完整
+type Writer interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// NewWriter 创建指定格式的 Writer
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// csvWriter CSV 导出，写入 UTF-8 BOM 以便 Excel 正确识别中文
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (cw *csvWriter) WriteRow(values []interface{}) error {
+	cw.record = cw.record[:0]
+	for _, v := range values {
+		text := formatValue(v)
+		if _, ok := v.(string); ok {
+			text = escapeFormula(text)
+		}
+		cw.record = append(cw.record, text)
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return err
+	}
+	// 逐行刷新到底层 Writer，导出过程中不在内存中堆积
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// xlsxWriter XLSX 导出，使用 excelize 的流式写入，行数据超过内存阈值后写入临时文件
+type xlsxWriter struct {
+	out  io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: f, sw: sw}, nil
+}
+
+func (xw *xlsxWriter) WriteRow(values []interface{}) error {
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		switch v.(type) {
+		case int, int64, uint, uint64, float64, bool:
+			row[i] = v
+		default:
+			row[i] = formatValue(v)
+		}
+	}
+	return xw.sw.SetRow(cell, row)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+	if err := xw.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := xw.file.WriteTo(xw.out)
+	return err
+}
+
+// formatValue 将单元格的值转换为字符串
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.Format(TimeLayout)
+	case *time.Time:
+		if val == nil {
+			return ""
+		}
+		return formatValue(*val)
+	case uint:
+		return strconv.FormatUint(uint64(val), 10)
+	case int:
+		return strconv.Itoa(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// escapeFormula 以 = + - @ 等开头的文本会被表格软件当作公式执行（CSV 注入），加单引号前缀按文本处理
+// XLSX 中字符串单元格不会被当作公式，不需要处理
+func escapeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
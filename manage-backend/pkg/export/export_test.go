@@ -0,0 +1,114 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, f)
+
+	f, err = ParseFormat("XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, f)
+
+	_, err = ParseFormat("pdf")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatCSV)
+	require.NoError(t, err)
+
+	created := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	require.NoError(t, w.WriteRow([]interface{}{"ID", "用户名", "创建时间"}))
+	require.NoError(t, w.WriteRow([]interface{}{uint(1), "=cmd|' /C calc'!A0", created}))
+	require.NoError(t, w.WriteRow([]interface{}{-1, "alice, bob", time.Time{}}))
+	require.NoError(t, w.Close())
+
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, "\xEF\xBB\xBF"), "missing BOM")
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(out, "\xEF\xBB\xBF"))).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"ID", "用户名", "创建时间"},
+		{"1", "'=cmd|' /C calc'!A0", "2024-01-02 15:04:05"},
+		// 只转义字符串，数字不受影响
+		{"-1", "alice, bob", ""},
+	}, records)
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatXLSX)
+	require.NoError(t, err)
+
+	created := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	require.NoError(t, w.WriteRow([]interface{}{"ID", "Username", "Created At"}))
+	require.NoError(t, w.WriteRow([]interface{}{uint(1), "=SUM(A1)", created}))
+	require.NoError(t, w.Close())
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+
+	rows, err := f.GetRows("Sheet1")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"ID", "Username", "Created At"},
+		{"1", "=SUM(A1)", "2024-01-02 15:04:05"},
+	}, rows)
+
+	formula, err := f.GetCellFormula("Sheet1", "B2")
+	require.NoError(t, err)
+	assert.Empty(t, formula)
+}
+
+func TestReaderRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			require.NoError(t, err)
+			require.NoError(t, w.WriteRow([]interface{}{"username", "email"}))
+			require.NoError(t, w.WriteRow([]interface{}{"alice", "alice@example.com"}))
+			require.NoError(t, w.Close())
+
+			r, err := NewReader(&buf, format)
+			require.NoError(t, err)
+			defer r.Close()
+
+			var rows [][]string
+			for {
+				row, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				rows = append(rows, row)
+			}
+			assert.Equal(t, [][]string{{"username", "email"}, {"alice", "alice@example.com"}}, rows)
+		})
+	}
+}
+
+func TestFormatFromFileName(t *testing.T) {
+	f, err := FormatFromFileName("users.XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, f)
+
+	_, err = FormatFromFileName("users.txt")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,103 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// utf8BOM Excel 保存的 CSV 开头可能带有 BOM
+var utf8BOM = []byte("\xEF\xBB\xBF")
+
+// Reader 逐行读取表格，读完后返回 io.EOF
+type Reader interface {
+	Read() ([]string, error)
+	Close() error
+}
+
+// FormatFromFileName 根据文件扩展名判断格式
+func FormatFromFileName(name string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+// NewReader 创建指定格式的 Reader，XLSX 读取第一个工作表
+func NewReader(r io.Reader, format Format) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatXLSX:
+		return newXLSXReader(r)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// csvReader CSV 读取，跳过开头的 BOM，允许各行列数不同
+type csvReader struct {
+	r *csv.Reader
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	br := bufio.NewReader(r)
+	if prefix, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	return &csvReader{r: cr}, nil
+}
+
+func (cr *csvReader) Read() ([]string, error) {
+	return cr.r.Read()
+}
+
+func (cr *csvReader) Close() error {
+	return nil
+}
+
+// xlsxReader XLSX 读取，使用 excelize 的行迭代器
+type xlsxReader struct {
+	file *excelize.File
+	rows *excelize.Rows
+}
+
+func newXLSXReader(r io.Reader) (*xlsxReader, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		f.Close()
+		return nil, errors.New("workbook has no sheets")
+	}
+	rows, err := f.Rows(sheets[0])
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxReader{file: f, rows: rows}, nil
+}
+
+func (xr *xlsxReader) Read() ([]string, error) {
+	if !xr.rows.Next() {
+		if err := xr.rows.Error(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return xr.rows.Columns()
+}
+
+func (xr *xlsxReader) Close() error {
+	xr.rows.Close()
+	return xr.file.Close()
+}
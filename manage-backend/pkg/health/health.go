@@ -0,0 +1,157 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 组件和整体状态
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDraining = "draining" // 服务正在关闭，依赖可能仍然正常
+	StatusDegraded = "degraded" // 可选依赖不可用，服务仍可降级运行，不影响整体状态
+)
+
+// defaultTimeout 单个检查的默认超时时间
+const defaultTimeout = 3 * time.Second
+
+// CheckFunc 检查单个依赖，成功时返回依赖的版本信息
+type CheckFunc func(ctx context.Context) (version string, err error)
+
+// Component 单个依赖的检查结果
+type Component struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Version   string  `json:"version,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report 就绪检查报告
+type Report struct {
+	Status     string      `json:"status"`
+	Components []Component `json:"components"`
+	CheckedAt  time.Time   `json:"checked_at"`
+}
+
+// Up 所有依赖都正常时返回 true
+func (r Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Summary 返回只包含各组件状态的摘要，用于未认证的探针请求
+func (r Report) Summary() map[string]string {
+	summary := make(map[string]string, len(r.Components))
+	for _, c := range r.Components {
+		summary[c.Name] = c.Status
+	}
+	return summary
+}
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	optional bool
+}
+
+// Checker 依赖健康检查器
+// 各项检查并发执行，每项检查单独超时，任一检查失败时整体状态为 down
+type Checker struct {
+	mu       sync.RWMutex
+	checks   []check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewChecker 创建健康检查器，timeout <= 0 时使用默认值
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Register 注册依赖检查，同名检查会被替换
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.register(check{name: name, fn: fn})
+}
+
+// RegisterOptional 注册可选依赖检查，失败时组件状态为 degraded，整体状态不受影响
+func (c *Checker) RegisterOptional(name string, fn CheckFunc) {
+	c.register(check{name: name, fn: fn, optional: true})
+}
+
+func (c *Checker) register(chk check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.checks {
+		if c.checks[i].name == chk.name {
+			c.checks[i] = chk
+			return
+		}
+	}
+	c.checks = append(c.checks, chk)
+}
+
+// SetDraining 标记服务正在关闭，之后的检查结果整体状态为 draining
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// Check 执行所有检查，结果按注册顺序排列
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make([]check, len(c.checks))
+	copy(checks, c.checks)
+	c.mu.RUnlock()
+
+	components := make([]Component, len(checks))
+	var wg sync.WaitGroup
+	for i, chk := range checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			components[i] = c.run(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Components: components, CheckedAt: time.Now()}
+	for _, comp := range components {
+		if comp.Status == StatusDown {
+			report.Status = StatusDown
+			break
+		}
+	}
+	if c.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// run 执行单项检查
+func (c *Checker) run(ctx context.Context, chk check) Component {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	version, err := chk.fn(ctx)
+	comp := Component{
+		Name:      chk.name,
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Version:   version,
+	}
+	if err != nil {
+		comp.Status = StatusDown
+		if chk.optional {
+			comp.Status = StatusDegraded
+		}
+		comp.Error = err.Error()
+	}
+	return comp
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckerReportsEachComponent(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Register("database", func(ctx context.Context) (string, error) { return "16.2", nil })
+	checker.Register("redis", func(ctx context.Context) (string, error) { return "", errors.New("connection refused") })
+	checker.Register("slow", func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	report := checker.Check(context.Background())
+	assert.False(t, report.Up())
+	require.Len(t, report.Components, 3)
+
+	assert.Equal(t, Component{Name: "database", Status: StatusUp, Version: "16.2", LatencyMs: report.Components[0].LatencyMs}, report.Components[0])
+	assert.Equal(t, StatusDown, report.Components[1].Status)
+	assert.Equal(t, "connection refused", report.Components[1].Error)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Components[2].Error)
+
+	assert.Equal(t, map[string]string{"database": "up", "redis": "down", "slow": "down"}, report.Summary())
+}
+
+func TestCheckerRegisterReplaces(t *testing.T) {
+	checker := NewChecker(0)
+	checker.Register("redis", func(ctx context.Context) (string, error) { return "", errors.New("down") })
+	checker.Register("redis", func(ctx context.Context) (string, error) { return "7.2.4", nil })
+
+	report := checker.Check(context.Background())
+	assert.True(t, report.Up())
+	require.Len(t, report.Components, 1)
+	assert.Equal(t, "7.2.4", report.Components[0].Version)
+}
+
+func TestCheckerDraining(t *testing.T) {
+	checker := NewChecker(0)
+	checker.Register("database", func(ctx context.Context) (string, error) { return "16.2", nil })
+	require.True(t, checker.Check(context.Background()).Up())
+
+	checker.SetDraining()
+	report := checker.Check(context.Background())
+	assert.False(t, report.Up())
+	assert.Equal(t, StatusDraining, report.Status)
+	assert.Equal(t, StatusUp, report.Components[0].Status)
+}
+
+func TestCheckerOptionalDegraded(t *testing.T) {
+	checker := NewChecker(0)
+	checker.Register("database", func(ctx context.Context) (string, error) { return "16.2", nil })
+	checker.RegisterOptional("database_replicas", func(ctx context.Context) (string, error) { return "", errors.New("0/1 replicas healthy") })
+
+	report := checker.Check(context.Background())
+	assert.True(t, report.Up())
+	assert.Equal(t, StatusDegraded, report.Components[1].Status)
+	assert.Equal(t, map[string]string{"database": "up", "database_replicas": "degraded"}, report.Summary())
+}
@@ -0,0 +1,343 @@
+// Package jobs 提供进程内的后台文件生成任务（如大数据量导出）
+// 任务结果写入本地目录，完成后可下载，过期后自动删除；任务状态只保存在当前实例，
+// 多实例部署时需要让同一用户的请求落到同一实例（或共享结果目录并改用外部存储保存状态）
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrNotFound 任务不存在或已过期
+var ErrNotFound = errors.New("job not found")
+
+// ErrNotReady 任务尚未成功完成，没有可下载的结果
+var ErrNotReady = errors.New("job result is not ready")
+
+// ErrShuttingDown 服务正在关闭，不再接受新任务
+var ErrShuttingDown = errors.New("job manager is shutting down")
+
+// partialSuffix 生成中的结果文件后缀
+const partialSuffix = ".partial"
+
+// Status 任务状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // 等待执行（并发数已满）
+	StatusRunning   Status = "running"   // 执行中
+	StatusSucceeded Status = "succeeded" // 完成，可以下载
+	StatusFailed    Status = "failed"    // 失败
+	StatusCanceled  Status = "canceled"  // 服务关闭时被取消
+)
+
+// Func 任务函数，结果写入 w，通过 progress 报告已处理的记录数；ctx 在服务关闭时取消
+type Func func(ctx context.Context, w io.Writer, progress func(n int64)) error
+
+// Job 后台任务
+type Job struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Status    Status     `json:"status"`
+	Progress  int64      `json:"progress"` // 已处理的记录数
+	Error     string     `json:"error,omitempty"` // 面向客户端的错误说明，详细原因只记录在日志中
+	FileName  string     `json:"file_name"` // 下载时的文件名
+	CreatedAt time.Time  `json:"created_at"`
+	DoneAt    *time.Time `json:"done_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 结果文件的删除时间
+
+	Owner       uint   `json:"-"`
+	ContentType string `json:"-"`
+
+	path string
+}
+
+// job 任务的内部状态，Job 字段由 Manager.mu 保护
+type job struct {
+	Job
+	progress atomic.Int64
+}
+
+// Options 任务管理器配置
+type Options struct {
+	Dir           string        // 结果文件目录
+	TTL           time.Duration // 结果保留时间
+	MaxConcurrent int           // 同时运行的任务数
+}
+
+// Manager 后台任务管理器
+type Manager struct {
+	opts Options
+
+	mu   sync.Mutex
+	jobs map[string]*job
+
+	sem    chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// NewManager 创建任务管理器，并清理上次运行遗留的结果文件
+func NewManager(opts Options) (*Manager, error) {
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = 1
+	}
+	if err := os.MkdirAll(opts.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create job dir: %w", err)
+	}
+	// 任务状态不持久化，重启后旧的结果文件无法再下载
+	entries, err := os.ReadDir(opts.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job dir: %w", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), "job-") {
+			os.Remove(filepath.Join(opts.Dir, e.Name()))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		opts:   opts,
+		jobs:   make(map[string]*job),
+		sem:    make(chan struct{}, opts.MaxConcurrent),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	m.wg.Add(1)
+	go m.cleanupLoop()
+	return m, nil
+}
+
+// Submit 提交任务，立即返回任务快照
+func (m *Manager) Submit(kind string, owner uint, fileName, contentType string, fn Func) (Job, error) {
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+	j := &job{Job: Job{
+		ID:          id,
+		Kind:        kind,
+		Status:      StatusPending,
+		FileName:    fileName,
+		CreatedAt:   time.Now(),
+		Owner:       owner,
+		ContentType: contentType,
+		path:        filepath.Join(m.opts.Dir, "job-"+id+filepath.Ext(fileName)),
+	}}
+
+	// 在锁内检查是否正在关闭，保证 Shutdown 等待时不会再有新任务加入
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return Job{}, ErrShuttingDown
+	}
+	m.jobs[id] = j
+	m.wg.Add(1)
+	m.mu.Unlock()
+
+	go m.run(j, fn)
+
+	logger.Info("后台任务已提交", zap.String("job_id", id), zap.String("kind", kind), zap.Uint("owner", owner))
+	return m.snapshot(j), nil
+}
+
+// Get 获取任务快照，owner 不匹配时视为不存在
+func (m *Manager) Get(id string, owner uint) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok || j.Owner != owner {
+		return Job{}, ErrNotFound
+	}
+	return m.snapshotLocked(j), nil
+}
+
+// Open 打开已完成任务的结果文件
+func (m *Manager) Open(id string, owner uint) (Job, *os.File, error) {
+	job, err := m.Get(id, owner)
+	if err != nil {
+		return Job{}, nil, err
+	}
+	if job.Status != StatusSucceeded {
+		return job, nil, fmt.Errorf("%w: job is %s", ErrNotReady, job.Status)
+	}
+	f, err := os.Open(job.path)
+	if err != nil {
+		return job, nil, err
+	}
+	return job, f, nil
+}
+
+// run 等待并发名额后执行任务，结果先写入 .partial 文件，成功后再重命名
+func (m *Manager) run(j *job, fn Func) {
+	defer m.wg.Done()
+
+	select {
+	case m.sem <- struct{}{}:
+		defer func() { <-m.sem }()
+	case <-m.ctx.Done():
+		m.finish(j, StatusCanceled)
+		return
+	}
+	m.setStatus(j, StatusRunning)
+
+	err := m.execute(j, fn)
+	switch {
+	case err == nil:
+		m.finish(j, StatusSucceeded)
+		logger.Info("后台任务完成", zap.String("job_id", j.ID), zap.Int64("progress", j.progress.Load()))
+	case m.ctx.Err() != nil:
+		m.finish(j, StatusCanceled)
+		logger.Warn("后台任务已取消", zap.String("job_id", j.ID), zap.Error(err))
+	default:
+		m.finish(j, StatusFailed)
+		logger.Error("后台任务失败", zap.String("job_id", j.ID), zap.Error(err))
+	}
+}
+
+func (m *Manager) execute(j *job, fn Func) (err error) {
+	partial := j.path + partialSuffix
+	f, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		if err != nil {
+			f.Close()
+			os.Remove(partial)
+		}
+	}()
+
+	if err = fn(m.ctx, f, j.progress.Store); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(partial, j.path)
+}
+
+func (m *Manager) setStatus(j *job, status Status) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j.Status = status
+}
+
+// finish 记录任务结束状态
+// 错误详情（可能包含数据库、驱动的报错）只写入日志，Job.Error 只给出通用说明
+func (m *Manager) finish(j *job, status Status) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	expires := now.Add(m.opts.TTL)
+	j.Status = status
+	j.DoneAt = &now
+	j.ExpiresAt = &expires
+	switch status {
+	case StatusFailed:
+		j.Error = "job failed"
+	case StatusCanceled:
+		j.Error = "job canceled because the server is shutting down"
+	}
+}
+
+func (m *Manager) snapshot(j *job) Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshotLocked(j)
+}
+
+func (m *Manager) snapshotLocked(j *job) Job {
+	snapshot := j.Job
+	snapshot.Progress = j.progress.Load()
+	return snapshot
+}
+
+// cleanupLoop 定期删除过期任务及其结果文件
+func (m *Manager) cleanupLoop() {
+	defer m.wg.Done()
+
+	interval := m.opts.TTL / 10
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.removeExpired(now)
+		}
+	}
+}
+
+// removeExpired 删除在 now 之前过期的任务
+func (m *Manager) removeExpired(now time.Time) {
+	m.mu.Lock()
+	var expired []*job
+	for id, j := range m.jobs {
+		if j.ExpiresAt != nil && now.After(*j.ExpiresAt) {
+			expired = append(expired, j)
+			delete(m.jobs, id)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, j := range expired {
+		if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("删除过期任务文件失败", zap.String("job_id", j.ID), zap.Error(err))
+		}
+	}
+}
+
+// Shutdown 停止接受新任务，取消进行中的任务并等待退出
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newID 生成任务ID
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
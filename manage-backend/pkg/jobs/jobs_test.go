@@ -0,0 +1,174 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestManager(t *testing.T, opts Options) *Manager {
+	t.Helper()
+	prev := logger.Logger
+	logger.Logger = zap.NewNop()
+	t.Cleanup(func() { logger.Logger = prev })
+
+	if opts.Dir == "" {
+		opts.Dir = t.TempDir()
+	}
+	if opts.TTL == 0 {
+		opts.TTL = time.Hour
+	}
+	m, err := NewManager(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { m.Shutdown(context.Background()) })
+	return m
+}
+
+// waitDone 等待任务结束
+func waitDone(t *testing.T, m *Manager, id string, owner uint) Job {
+	t.Helper()
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(id, owner)
+		require.NoError(t, err)
+		return job.DoneAt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestManagerSubmitAndOpen(t *testing.T) {
+	m := newTestManager(t, Options{})
+
+	job, err := m.Submit("export", 1, "users.csv", "text/csv", func(ctx context.Context, w io.Writer, progress func(int64)) error {
+		_, err := io.WriteString(w, "id\n1\n")
+		progress(1)
+		return err
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, job.ID)
+
+	job = waitDone(t, m, job.ID, 1)
+	assert.Equal(t, StatusSucceeded, job.Status)
+	assert.Equal(t, int64(1), job.Progress)
+	assert.NotNil(t, job.ExpiresAt)
+
+	got, f, err := m.Open(job.ID, 1)
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "id\n1\n", string(data))
+	assert.Equal(t, "users.csv", got.FileName)
+	assert.Equal(t, "text/csv", got.ContentType)
+
+	// 其他用户看不到该任务
+	_, err = m.Get(job.ID, 2)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, _, err = m.Open(job.ID, 2)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManagerFailedJob(t *testing.T) {
+	dir := t.TempDir()
+	m := newTestManager(t, Options{Dir: dir})
+
+	job, err := m.Submit("export", 1, "users.csv", "text/csv", func(ctx context.Context, w io.Writer, progress func(int64)) error {
+		io.WriteString(w, "partial")
+		return errors.New("boom")
+	})
+	require.NoError(t, err)
+
+	job = waitDone(t, m, job.ID, 1)
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Equal(t, "job failed", job.Error, "error details are not exposed")
+
+	_, _, err = m.Open(job.ID, 1)
+	assert.ErrorIs(t, err, ErrNotReady)
+
+	// 失败任务不留下结果文件
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestManagerRemoveExpired(t *testing.T) {
+	dir := t.TempDir()
+	m := newTestManager(t, Options{Dir: dir, TTL: time.Minute})
+
+	job, err := m.Submit("export", 1, "users.csv", "text/csv", func(ctx context.Context, w io.Writer, progress func(int64)) error {
+		_, err := io.WriteString(w, "id\n")
+		return err
+	})
+	require.NoError(t, err)
+	job = waitDone(t, m, job.ID, 1)
+	require.Equal(t, StatusSucceeded, job.Status)
+
+	m.removeExpired(time.Now())
+	_, err = m.Get(job.ID, 1)
+	require.NoError(t, err)
+
+	m.removeExpired(job.ExpiresAt.Add(time.Second))
+	_, err = m.Get(job.ID, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+	matches, err := filepath.Glob(filepath.Join(dir, "job-*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestManagerShutdownCancelsJobs(t *testing.T) {
+	m := newTestManager(t, Options{MaxConcurrent: 1})
+
+	started := make(chan struct{})
+	running, err := m.Submit("export", 1, "a.csv", "text/csv", func(ctx context.Context, w io.Writer, progress func(int64)) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+	<-started
+
+	// 并发数已满，第二个任务排队等待
+	queued, err := m.Submit("export", 1, "b.csv", "text/csv", func(ctx context.Context, w io.Writer, progress func(int64)) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, queued.Status)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Shutdown(ctx))
+
+	for _, id := range []string{running.ID, queued.ID} {
+		job, err := m.Get(id, 1)
+		require.NoError(t, err)
+		assert.Equal(t, StatusCanceled, job.Status)
+	}
+
+	_, err = m.Submit("export", 1, "c.csv", "text/csv", func(ctx context.Context, w io.Writer, progress func(int64)) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrShuttingDown)
+}
+
+func TestNewManagerRemovesLeftoverFiles(t *testing.T) {
+	dir := t.TempDir()
+	leftover := filepath.Join(dir, "job-old.csv")
+	keep := filepath.Join(dir, "README")
+	require.NoError(t, os.WriteFile(leftover, []byte("x"), 0o600))
+	require.NoError(t, os.WriteFile(keep, []byte("x"), 0o600))
+
+	newTestManager(t, Options{Dir: dir})
+
+	assert.NoFileExists(t, leftover)
+	assert.FileExists(t, keep)
+}
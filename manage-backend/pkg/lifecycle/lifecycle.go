@@ -0,0 +1,140 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// HookFunc 关闭钩子，ctx 在关闭超时后取消
+type HookFunc func(ctx context.Context) error
+
+// Options 服务关闭配置
+type Options struct {
+	// 收到退出信号后，先将就绪检查标记为未就绪并等待该时长，让负载均衡摘除实例
+	DrainPeriod time.Duration
+	// 停止 HTTP 服务和执行关闭钩子的总超时时间
+	ShutdownTimeout time.Duration
+}
+
+type hook struct {
+	name string
+	fn   HookFunc
+}
+
+// Manager 服务生命周期管理
+// 关闭顺序：执行 OnDrain 回调 -> 等待 DrainPeriod -> 停止 HTTP 服务（等待进行中的请求完成）-> 按注册的逆序执行关闭钩子。
+// 逆序执行使后初始化的组件（如后台任务）先于其依赖（如 Redis、数据库连接池）关闭。
+type Manager struct {
+	mu      sync.Mutex
+	hooks   []hook
+	onDrain []func()
+	closed  bool
+}
+
+// NewManager 创建生命周期管理器
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// OnShutdown 注册关闭钩子
+func (m *Manager) OnShutdown(name string, fn HookFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// OnDrain 注册进入排空阶段时的回调（如将就绪检查标记为未就绪）
+func (m *Manager) OnDrain(fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onDrain = append(m.onDrain, fn)
+}
+
+// Run 启动 HTTP 服务并阻塞，直到收到 SIGINT/SIGTERM 或服务异常退出，然后按顺序关闭
+func (m *Manager) Run(srv *http.Server, opts Options) error {
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info("服务器正在启动", zap.String("addr", srv.Addr))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	var serveErr error
+	select {
+	case sig := <-sigCh:
+		logger.Info("收到退出信号，开始关闭服务", zap.String("signal", sig.String()))
+		m.drain(opts.DrainPeriod)
+	case err := <-errCh:
+		serveErr = fmt.Errorf("http server: %w", err)
+		logger.Error("服务器异常退出", zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("HTTP 服务关闭超时，强制断开剩余连接", zap.Error(err))
+		srv.Close()
+	} else {
+		logger.Info("HTTP 服务已停止")
+	}
+
+	return errors.Join(serveErr, m.Shutdown(ctx))
+}
+
+// drain 进入排空阶段
+func (m *Manager) drain(period time.Duration) {
+	m.mu.Lock()
+	callbacks := append([]func(){}, m.onDrain...)
+	m.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+	if period > 0 {
+		logger.Info("等待负载均衡摘除实例", zap.Duration("drain_period", period))
+		time.Sleep(period)
+	}
+}
+
+// Shutdown 按注册的逆序执行关闭钩子，只执行一次
+// 某个钩子失败不影响后续钩子，所有错误合并返回
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		start := time.Now()
+		if err := h.fn(ctx); err != nil {
+			logger.Error("关闭钩子执行失败", zap.String("hook", h.name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+		logger.Info("关闭钩子执行完成", zap.String("hook", h.name), zap.Duration("elapsed", time.Since(start)))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestShutdownRunsHooksInReverseOrder(t *testing.T) {
+	original := logger.Logger
+	logger.Logger = zap.NewNop()
+	t.Cleanup(func() { logger.Logger = original })
+
+	m := NewManager()
+	var order []string
+	m.OnShutdown("database", func(ctx context.Context) error {
+		order = append(order, "database")
+		return nil
+	})
+	m.OnShutdown("redis", func(ctx context.Context) error {
+		order = append(order, "redis")
+		return errors.New("already closed")
+	})
+	m.OnShutdown("worker", func(ctx context.Context) error {
+		order = append(order, "worker")
+		return nil
+	})
+
+	err := m.Shutdown(context.Background())
+	assert.Equal(t, []string{"worker", "redis", "database"}, order)
+	assert.ErrorContains(t, err, "redis: already closed")
+
+	// 只执行一次
+	assert.NoError(t, m.Shutdown(context.Background()))
+	assert.Len(t, order, 3)
+}
+
+func TestDrainCallsCallbacks(t *testing.T) {
+	m := NewManager()
+	drained := false
+	m.OnDrain(func() { drained = true })
+	m.drain(0)
+	assert.True(t, drained)
+}
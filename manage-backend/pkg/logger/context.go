@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type (
+	// requestIDKey 请求ID在 context 中的键
+	requestIDKey struct{}
+	// userKey 当前用户在 context 中的键
+	userKey struct{}
+)
+
+// contextUser context 中的用户信息
+type contextUser struct {
+	id       uint
+	username string
+}
+
+// WithRequestID 将请求ID写入 context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext 从 context 中获取请求ID
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithUser 将当前用户写入 context，之后的日志会带上 user_id 和 username
+func WithUser(ctx context.Context, userID uint, username string) context.Context {
+	return context.WithValue(ctx, userKey{}, contextUser{id: userID, username: username})
+}
+
+// contextFields 从 context 中提取请求ID、用户和链路追踪字段
+func contextFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	var fields []zap.Field
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+	if user, ok := ctx.Value(userKey{}).(contextUser); ok {
+		fields = append(fields,
+			zap.Uint("user_id", user.id),
+			zap.String("username", user.username))
+	}
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if spanCtx.IsValid() {
+		fields = append(fields,
+			zap.String("trace_id", spanCtx.TraceID().String()),
+			zap.String("span_id", spanCtx.SpanID().String()))
+	}
+	return fields
+}
+
+// FromContext 返回带有请求ID、用户和链路追踪字段的子日志器
+// context 中没有这些信息时直接返回全局日志器
+func FromContext(ctx context.Context) *zap.Logger {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return Logger
+	}
+	return Logger.With(fields...)
+}
+
+// DebugContext 记录调试日志，并附加 context 中的请求ID、用户和链路追踪字段
+func DebugContext(ctx context.Context, msg string, fields ...zap.Field) {
+	Logger.Debug(msg, append(fields, contextFields(ctx)...)...)
+}
+
+// InfoContext 记录信息日志，并附加 context 中的请求ID、用户和链路追踪字段
+func InfoContext(ctx context.Context, msg string, fields ...zap.Field) {
+	Logger.Info(msg, append(fields, contextFields(ctx)...)...)
+}
+
+// WarnContext 记录警告日志，并附加 context 中的请求ID、用户和链路追踪字段
+func WarnContext(ctx context.Context, msg string, fields ...zap.Field) {
+	Logger.Warn(msg, append(fields, contextFields(ctx)...)...)
+}
+
+// ErrorContext 记录错误日志，并附加 context 中的请求ID、用户和链路追踪字段
+func ErrorContext(ctx context.Context, msg string, fields ...zap.Field) {
+	Logger.Error(msg, append(fields, contextFields(ctx)...)...)
+}
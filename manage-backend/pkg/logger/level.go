@@ -0,0 +1,314 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levels 运行时日志级别
+// 全局级别对所有日志生效，命名日志器（如 service.user、gorm）可单独覆盖；
+// 覆盖按名称前缀匹配，service 会同时作用于 service.user 和 service.session
+var levels = newLevelRegistry()
+
+// LevelStatus 当前日志级别状态
+type LevelStatus struct {
+	Global  string            `json:"global"`
+	Named   map[string]string `json:"named"`
+	Reverts map[string]string `json:"reverts,omitempty"` // 名称 -> 自动恢复时间（RFC3339），全局级别的名称为 global
+}
+
+// levelRegistry 保存全局和命名日志器的级别
+type levelRegistry struct {
+	global zap.AtomicLevel
+
+	mu      sync.RWMutex
+	named   map[string]zapcore.Level
+	minimum zapcore.Level
+	timers  map[string]*revertTimer
+}
+
+// revertTimer 级别自动恢复定时器
+type revertTimer struct {
+	timer *time.Timer
+	at    time.Time
+
+	// 到期后恢复的级别，exists 为 false 表示删除命名覆盖
+	level  zapcore.Level
+	exists bool
+}
+
+func newLevelRegistry() *levelRegistry {
+	return &levelRegistry{
+		global:  zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		named:   make(map[string]zapcore.Level),
+		minimum: zapcore.InfoLevel,
+		timers:  make(map[string]*revertTimer),
+	}
+}
+
+// enabled 判断指定名称的日志器是否输出该级别
+func (r *levelRegistry) enabled(name string, lvl zapcore.Level) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.named) > 0 && name != "" {
+		if override, ok := r.matchLocked(name); ok {
+			return lvl >= override
+		}
+	}
+	return r.global.Enabled(lvl)
+}
+
+// matchLocked 查找最长前缀匹配的命名级别（调用方需持有读锁）
+func (r *levelRegistry) matchLocked(name string) (zapcore.Level, bool) {
+	for {
+		if lvl, ok := r.named[name]; ok {
+			return lvl, true
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
+
+// anyEnabled 任意日志器可能输出该级别时返回 true，用于 Core.Enabled 的快速判断
+func (r *levelRegistry) anyEnabled(lvl zapcore.Level) bool {
+	if r.global.Enabled(lvl) {
+		return true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.named) > 0 && lvl >= r.minimum
+}
+
+// recomputeMinimumLocked 重新计算命名级别中的最低级别（调用方需持有写锁）
+func (r *levelRegistry) recomputeMinimumLocked() {
+	r.minimum = zapcore.FatalLevel
+	for _, lvl := range r.named {
+		if lvl < r.minimum {
+			r.minimum = lvl
+		}
+	}
+}
+
+// set 设置级别，name 为空时设置全局级别；ttl > 0 时到期后恢复为之前的级别
+func (r *levelRegistry) set(name string, lvl zapcore.Level, ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 新的设置会取消同名的待恢复任务，恢复目标仍以第一次临时调整前的级别为准
+	prevLevel, prevExists := r.currentLocked(name)
+	if pending, ok := r.timers[name]; ok {
+		pending.timer.Stop()
+		delete(r.timers, name)
+		prevLevel, prevExists = pending.level, pending.exists
+	}
+
+	r.applyLocked(name, lvl, true)
+
+	if ttl > 0 {
+		t := &revertTimer{at: time.Now().Add(ttl), level: prevLevel, exists: prevExists}
+		t.timer = time.AfterFunc(ttl, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if r.timers[name] != t {
+				return
+			}
+			delete(r.timers, name)
+			r.applyLocked(name, prevLevel, prevExists)
+			if Logger != nil {
+				Logger.Info("日志级别已自动恢复",
+					zap.String("logger", displayName(name)),
+					zap.String("level", levelString(prevLevel, prevExists)))
+			}
+		})
+		r.timers[name] = t
+	}
+}
+
+// clear 删除命名日志器的级别覆盖
+func (r *levelRegistry) clear(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if pending, ok := r.timers[name]; ok {
+		pending.timer.Stop()
+		delete(r.timers, name)
+	}
+	r.applyLocked(name, 0, false)
+}
+
+// currentLocked 获取当前级别（调用方需持有锁）
+func (r *levelRegistry) currentLocked(name string) (zapcore.Level, bool) {
+	if name == "" {
+		return r.global.Level(), true
+	}
+	lvl, ok := r.named[name]
+	return lvl, ok
+}
+
+// applyLocked 应用级别，exists 为 false 表示删除命名覆盖（调用方需持有写锁）
+func (r *levelRegistry) applyLocked(name string, lvl zapcore.Level, exists bool) {
+	if name == "" {
+		r.global.SetLevel(lvl)
+		return
+	}
+	if exists {
+		r.named[name] = lvl
+	} else {
+		delete(r.named, name)
+	}
+	r.recomputeMinimumLocked()
+}
+
+// status 返回当前级别状态
+func (r *levelRegistry) status() LevelStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	st := LevelStatus{
+		Global: r.global.Level().String(),
+		Named:  make(map[string]string, len(r.named)),
+	}
+	for name, lvl := range r.named {
+		st.Named[name] = lvl.String()
+	}
+	if len(r.timers) > 0 {
+		st.Reverts = make(map[string]string, len(r.timers))
+		for name, t := range r.timers {
+			st.Reverts[displayName(name)] = t.at.Format(time.RFC3339)
+		}
+	}
+	return st
+}
+
+// ParseLevel 解析日志级别字符串（debug, info, warn, error）
+func ParseLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "error":
+		return parseLevel(strings.ToLower(level)), nil
+	default:
+		return 0, fmt.Errorf("invalid log level: %q", level)
+	}
+}
+
+// SetLevel 设置日志级别
+// name 为空时设置全局级别，否则设置命名日志器（及其子日志器）的级别；
+// ttl > 0 时到期后自动恢复为调整前的级别
+func SetLevel(name, level string, ttl time.Duration) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.set(name, lvl, ttl)
+	return nil
+}
+
+// ClearLevel 删除命名日志器的级别覆盖，恢复使用全局级别
+func ClearLevel(name string) {
+	if name == "" {
+		return
+	}
+	levels.clear(name)
+}
+
+// Levels 返回当前日志级别状态
+func Levels() LevelStatus {
+	return levels.status()
+}
+
+// levelCore 按日志器名称过滤级别的 zapcore.Core 包装
+type levelCore struct {
+	zapcore.Core
+	registry *levelRegistry
+}
+
+func newLevelCore(core zapcore.Core, registry *levelRegistry) zapcore.Core {
+	return &levelCore{Core: core, registry: registry}
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return c.registry.anyEnabled(lvl)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), registry: c.registry}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.registry.enabled(ent.LoggerName, ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// Component 命名日志器，用于按模块单独调整日志级别
+type Component string
+
+// Logger 返回该组件的 zap 日志器
+func (n Component) Logger() *zap.Logger {
+	return Logger.Named(string(n))
+}
+
+func (n Component) Debug(msg string, fields ...zap.Field) {
+	n.Logger().Debug(msg, fields...)
+}
+
+func (n Component) Info(msg string, fields ...zap.Field) {
+	n.Logger().Info(msg, fields...)
+}
+
+func (n Component) Warn(msg string, fields ...zap.Field) {
+	n.Logger().Warn(msg, fields...)
+}
+
+func (n Component) Error(msg string, fields ...zap.Field) {
+	n.Logger().Error(msg, fields...)
+}
+
+// Enabled 判断该组件当前是否输出指定级别的日志
+func (n Component) Enabled(lvl zapcore.Level) bool {
+	return levels.enabled(string(n), lvl)
+}
+
+// DebugContext 记录调试日志，并附加 context 中的请求ID、用户和链路追踪字段
+func (n Component) DebugContext(ctx context.Context, msg string, fields ...zap.Field) {
+	n.Logger().Debug(msg, append(fields, contextFields(ctx)...)...)
+}
+
+// InfoContext 记录信息日志，并附加 context 中的请求ID、用户和链路追踪字段
+func (n Component) InfoContext(ctx context.Context, msg string, fields ...zap.Field) {
+	n.Logger().Info(msg, append(fields, contextFields(ctx)...)...)
+}
+
+// WarnContext 记录警告日志，并附加 context 中的请求ID、用户和链路追踪字段
+func (n Component) WarnContext(ctx context.Context, msg string, fields ...zap.Field) {
+	n.Logger().Warn(msg, append(fields, contextFields(ctx)...)...)
+}
+
+// ErrorContext 记录错误日志，并附加 context 中的请求ID、用户和链路追踪字段
+func (n Component) ErrorContext(ctx context.Context, msg string, fields ...zap.Field) {
+	n.Logger().Error(msg, append(fields, contextFields(ctx)...)...)
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "global"
+	}
+	return name
+}
+
+func levelString(lvl zapcore.Level, exists bool) string {
+	if !exists {
+		return "inherit"
+	}
+	return lvl.String()
+}
@@ -1,36 +1,134 @@
 package logger
 
 import (
+	"fmt"
+	"os"
+	"time"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// 日志输出方式
+const (
+	OutputStdout = "stdout" // 标准输出
+	OutputFile   = "file"   // 滚动日志文件
+	OutputBoth   = "both"   // 同时输出到标准输出和文件
 )
 
 var Logger *zap.Logger
 
+// FileOptions 日志文件滚动配置，0 值使用默认值
+type FileOptions struct {
+	Path       string // 日志文件路径，默认 logs/app.log
+	MaxSizeMB  int    // 单个文件最大大小（MB），默认 100
+	MaxBackups int    // 保留的旧文件个数，默认 7
+	MaxAgeDays int    // 旧文件保留天数，默认 30
+	Compress   bool   // 是否压缩旧文件
+}
+
+// Options 日志器配置
+type Options struct {
+	Level  string
+	Output string // stdout(默认), file, both
+	File   FileOptions
+
+	// 需要脱敏的字段名（不区分大小写），为 nil 时使用 DefaultRedactFields
+	RedactFields []string
+}
+
+// Init 使用默认配置初始化日志器（输出到标准输出）
 func Init(level string) {
-	var zapLevel zapcore.Level
+	if err := InitWithOptions(Options{Level: level}); err != nil {
+		panic(err)
+	}
+}
+
+// InitWithOptions 根据配置初始化日志器
+func InitWithOptions(opts Options) error {
+	writer, err := buildWriteSyncer(opts)
+	if err != nil {
+		return err
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	// 级别由最外层的 levelCore 控制，底层 Core 放行所有级别
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), writer, zapcore.DebugLevel)
+
+	redactFields := opts.RedactFields
+	if redactFields == nil {
+		redactFields = DefaultRedactFields
+	}
+	core = NewRedactCore(core, redactFields)
+
+	// 与 zap.NewProductionConfig 一致的采样策略：每秒同一条日志前 100 条全部输出，之后每 100 条输出 1 条
+	core = zapcore.NewSamplerWithOptions(core, time.Second, 100, 100)
+
+	// 运行时可调整的全局级别和命名日志器级别，见 SetLevel
+	levels.global.SetLevel(parseLevel(opts.Level))
+	core = newLevelCore(core, levels)
+
+	Logger = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel), zap.ErrorOutput(zapcore.Lock(os.Stderr)))
+	return nil
+}
+
+// parseLevel 解析日志级别，无法识别时使用 info
+func parseLevel(level string) zapcore.Level {
 	switch level {
 	case "debug":
-		zapLevel = zapcore.DebugLevel
+		return zapcore.DebugLevel
 	case "info":
-		zapLevel = zapcore.InfoLevel
+		return zapcore.InfoLevel
 	case "warn":
-		zapLevel = zapcore.WarnLevel
+		return zapcore.WarnLevel
 	case "error":
-		zapLevel = zapcore.ErrorLevel
+		return zapcore.ErrorLevel
 	default:
-		zapLevel = zapcore.InfoLevel
+		return zapcore.InfoLevel
 	}
+}
 
-	config := zap.NewProductionConfig()
-	config.Level = zap.NewAtomicLevelAt(zapLevel)
-	config.EncoderConfig.TimeKey = "timestamp"
-	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+// buildWriteSyncer 根据输出方式创建日志写入目标
+func buildWriteSyncer(opts Options) (zapcore.WriteSyncer, error) {
+	stdout := zapcore.Lock(os.Stdout)
 
-	var err error
-	Logger, err = config.Build()
-	if err != nil {
-		panic(err)
+	switch opts.Output {
+	case OutputStdout, "":
+		return stdout, nil
+	case OutputFile:
+		return zapcore.AddSync(newRotatingFile(opts.File)), nil
+	case OutputBoth:
+		return zapcore.NewMultiWriteSyncer(stdout, zapcore.AddSync(newRotatingFile(opts.File))), nil
+	default:
+		return nil, fmt.Errorf("unknown log output: %s", opts.Output)
+	}
+}
+
+// newRotatingFile 创建按大小滚动的日志文件
+func newRotatingFile(opts FileOptions) *lumberjack.Logger {
+	if opts.Path == "" {
+		opts.Path = "logs/app.log"
+	}
+	if opts.MaxSizeMB <= 0 {
+		opts.MaxSizeMB = 100
+	}
+	if opts.MaxBackups <= 0 {
+		opts.MaxBackups = 7
+	}
+	if opts.MaxAgeDays <= 0 {
+		opts.MaxAgeDays = 30
+	}
+	return &lumberjack.Logger{
+		Filename:   opts.Path,
+		MaxSize:    opts.MaxSizeMB,
+		MaxBackups: opts.MaxBackups,
+		MaxAge:     opts.MaxAgeDays,
+		Compress:   opts.Compress,
+		LocalTime:  true,
 	}
 }
 
@@ -52,4 +150,4 @@ func Error(msg string, fields ...zap.Field) {
 
 func Fatal(msg string, fields ...zap.Field) {
 	Logger.Fatal(msg, fields...)
-}
\ No newline at end of file
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestContextHelpersInjectTraceIDs(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	original := Logger
+	Logger = zap.New(core)
+	t.Cleanup(func() { Logger = original })
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	InfoContext(ctx, "with span", zap.String("k", "v"))
+	InfoContext(context.Background(), "without span")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "v", fields["k"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), fields["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), fields["span_id"])
+
+	assert.NotContains(t, entries[1].ContextMap(), "trace_id")
+}
+
+func TestRedactCore(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(NewRedactCore(core, DefaultRedactFields))
+
+	log.With(zap.String("refresh_token", "secret-refresh")).Info("login",
+		zap.String("email", "alice@example.com"),
+		zap.String("Password", "plain"),
+		zap.String("ip_address", "192.168.1.23"),
+		zap.Int("captcha_id", 42),
+		zap.String("username", "alice"))
+
+	require.Len(t, logs.All(), 1)
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "a***@example.com", fields["email"])
+	assert.Equal(t, "[REDACTED]", fields["Password"])
+	assert.Equal(t, "192.168.1.*", fields["ip_address"])
+	assert.Equal(t, "[REDACTED]", fields["captcha_id"])
+	assert.Equal(t, "[REDACTED]", fields["refresh_token"])
+	assert.Equal(t, "alice", fields["username"])
+}
+
+func TestRedactCorePrefixedKeys(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(NewRedactCore(core, DefaultRedactFields))
+
+	log.Debug("update email",
+		zap.String("old_email", "alice@example.com"),
+		zap.String("New_Email", "bob@example.org"),
+		zap.String("user_refresh_token", "secret-refresh"),
+		zap.String("remote_client_ip", "10.0.0.8"),
+		zap.String("emails_sent", "3"),
+		zap.String("email_domain", "example.com"))
+
+	require.Len(t, logs.All(), 1)
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "a***@example.com", fields["old_email"])
+	assert.Equal(t, "b***@example.org", fields["New_Email"])
+	assert.Equal(t, "[REDACTED]", fields["user_refresh_token"])
+	assert.Equal(t, "10.0.0.*", fields["remote_client_ip"])
+	assert.Equal(t, "3", fields["emails_sent"])
+	assert.Equal(t, "example.com", fields["email_domain"])
+}
+
+func TestFromContext(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	original := Logger
+	Logger = zap.New(core)
+	t.Cleanup(func() { Logger = original })
+
+	ctx := WithUser(WithRequestID(context.Background(), "req-1"), 7, "bob")
+	FromContext(ctx).Info("hello")
+	assert.Same(t, Logger, FromContext(context.Background()))
+
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Equal(t, uint64(7), fields["user_id"])
+	assert.Equal(t, "bob", fields["username"])
+}
+
+func TestLevelCoreNamedOverrides(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	registry := newLevelRegistry()
+	log := zap.New(newLevelCore(core, registry))
+
+	log.Named("service.user").Debug("hidden")
+	require.Zero(t, logs.Len())
+
+	registry.set("service", zapcore.DebugLevel, 0)
+	log.Named("service.user").Debug("visible")
+	log.Named("gorm").Debug("hidden")
+	log.Debug("hidden")
+	require.Equal(t, 1, logs.Len())
+
+	// 更具体的名称优先
+	registry.set("service.user", zapcore.ErrorLevel, 0)
+	log.Named("service.user").Warn("hidden")
+	log.Named("service.session").Debug("visible")
+	assert.Equal(t, 2, logs.Len())
+
+	registry.clear("service")
+	registry.clear("service.user")
+	log.Named("service.session").Debug("hidden")
+	assert.Equal(t, 2, logs.Len())
+	assert.Empty(t, registry.status().Named)
+}
+
+func TestLevelRegistryTTLReverts(t *testing.T) {
+	original := Logger
+	Logger = zap.NewNop()
+	t.Cleanup(func() { Logger = original })
+
+	registry := newLevelRegistry()
+	registry.set("", zapcore.DebugLevel, 20*time.Millisecond)
+	registry.set("gorm", zapcore.DebugLevel, 20*time.Millisecond)
+	// 重复设置不改变恢复目标
+	registry.set("gorm", zapcore.WarnLevel, 20*time.Millisecond)
+
+	st := registry.status()
+	assert.Equal(t, "debug", st.Global)
+	assert.Equal(t, "warn", st.Named["gorm"])
+	assert.Contains(t, st.Reverts, "global")
+	assert.Contains(t, st.Reverts, "gorm")
+
+	assert.Eventually(t, func() bool {
+		st := registry.status()
+		return st.Global == "info" && len(st.Named) == 0 && len(st.Reverts) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestSetLevelRejectsUnknownLevel(t *testing.T) {
+	assert.Error(t, SetLevel("", "verbose", 0))
+}
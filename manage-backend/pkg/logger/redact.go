@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue 完全脱敏后的占位值
+const redactedValue = "[REDACTED]"
+
+// DefaultRedactFields 默认脱敏的字段名
+var DefaultRedactFields = []string{
+	"password",
+	"token",
+	"access_token",
+	"refresh_token",
+	"authorization",
+	"secret",
+	"captcha_code",
+	"captcha_id",
+	"email",
+	"ip_address",
+	"client_ip",
+}
+
+// maskers 需要部分保留的字段使用专门的掩码规则，其余字段完全替换为 [REDACTED]
+var maskers = map[string]func(string) string{
+	"email":      maskEmail,
+	"ip_address": maskIP,
+	"client_ip":  maskIP,
+}
+
+// redactCore 在编码前对指定字段脱敏的 zapcore.Core 包装
+// 只按字段名匹配顶层字段，zap.Any 传入的结构体内部字段不会被处理
+// 字段名与配置相同或以 "_<配置字段>" 结尾（如 old_email、new_email）时脱敏
+type redactCore struct {
+	zapcore.Core
+	fields map[string]struct{}
+}
+
+// NewRedactCore 创建脱敏 Core，字段名不区分大小写
+func NewRedactCore(core zapcore.Core, fields []string) zapcore.Core {
+	if len(fields) == 0 {
+		return core
+	}
+	set := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		set[strings.ToLower(f)] = struct{}{}
+	}
+	return &redactCore{Core: core, fields: set}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redact(fields)), fields: c.fields}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.redact(fields))
+}
+
+// redact 返回脱敏后的字段，没有需要脱敏的字段时直接返回原切片
+func (c *redactCore) redact(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		name, ok := c.match(strings.ToLower(f.Key))
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields)
+		}
+		out[i] = zapcore.Field{Key: f.Key, Type: zapcore.StringType, String: maskValue(name, f)}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// match 返回字段名对应的脱敏字段，带前缀的字段（如 new_email）按后缀匹配
+func (c *redactCore) match(key string) (string, bool) {
+	if _, ok := c.fields[key]; ok {
+		return key, true
+	}
+	for i := strings.IndexByte(key, '_'); i >= 0; {
+		suffix := key[i+1:]
+		if _, ok := c.fields[suffix]; ok {
+			return suffix, true
+		}
+		next := strings.IndexByte(suffix, '_')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return "", false
+}
+
+// maskValue 计算字段的脱敏值
+func maskValue(key string, f zapcore.Field) string {
+	masker, ok := maskers[key]
+	if !ok {
+		return redactedValue
+	}
+
+	var value string
+	switch f.Type {
+	case zapcore.StringType:
+		value = f.String
+	case zapcore.ByteStringType:
+		b, _ := f.Interface.([]byte)
+		value = string(b)
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok {
+			value = s.String()
+		}
+	default:
+		return redactedValue
+	}
+	if value == "" {
+		return ""
+	}
+	return masker(value)
+}
+
+// maskEmail 保留首字母和域名，如 a***@example.com
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return redactedValue
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskIP 保留网段，IPv4 隐藏最后一段，IPv6 只保留前 3 组
+func maskIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return redactedValue
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.*", v4[0], v4[1], v4[2])
+	}
+	groups := strings.SplitN(parsed.String(), ":", 4)
+	if len(groups) < 4 {
+		return redactedValue
+	}
+	return strings.Join(groups[:3], ":") + ":*"
+}
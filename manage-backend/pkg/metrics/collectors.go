@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// collectTimeout 采集时访问外部存储的超时时间
+const collectTimeout = 2 * time.Second
+
+// RegisterDBStats 注册数据库连接池指标（来自 sql.DB.Stats()）
+func RegisterDBStats(db *sql.DB, dbName string) {
+	Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// RedisPoolStatser 能够提供连接池统计的 Redis 客户端
+type RedisPoolStatser interface {
+	PoolStats() *redis.PoolStats
+}
+
+// RegisterRedisPoolStats 注册 Redis 连接池指标
+func RegisterRedisPoolStats(client RedisPoolStatser) {
+	Register(&redisPoolCollector{client: client})
+}
+
+var (
+	redisPoolHitsDesc     = newRedisPoolDesc("hits_total", "连接池命中空闲连接的次数")
+	redisPoolMissesDesc   = newRedisPoolDesc("misses_total", "连接池未命中空闲连接的次数")
+	redisPoolTimeoutsDesc = newRedisPoolDesc("timeouts_total", "等待连接超时的次数")
+	redisPoolTotalDesc    = newRedisPoolDesc("total_connections", "连接池当前连接总数")
+	redisPoolIdleDesc     = newRedisPoolDesc("idle_connections", "连接池当前空闲连接数")
+	redisPoolStaleDesc    = newRedisPoolDesc("stale_connections_total", "被移除的过期连接数")
+)
+
+func newRedisPoolDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", name), help, nil, nil)
+}
+
+// redisPoolCollector Redis 连接池采集器
+type redisPoolCollector struct {
+	client RedisPoolStatser
+}
+
+func (c *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisPoolHitsDesc
+	ch <- redisPoolMissesDesc
+	ch <- redisPoolTimeoutsDesc
+	ch <- redisPoolTotalDesc
+	ch <- redisPoolIdleDesc
+	ch <- redisPoolStaleDesc
+}
+
+func (c *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	if stats == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(redisPoolHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(redisPoolMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(redisPoolTimeoutsDesc, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisPoolTotalDesc, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisPoolIdleDesc, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(redisPoolStaleDesc, prometheus.CounterValue, float64(stats.StaleConns))
+}
+
+// RegisterActiveSessions 注册活跃会话数指标
+// count 在每次采集时调用，出错时不上报该指标
+func RegisterActiveSessions(count func(ctx context.Context) (int64, error)) {
+	Register(&activeSessionsCollector{count: count})
+}
+
+var activeSessionsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "session", "active"),
+	"当前活跃会话数", nil, nil)
+
+// activeSessionsCollector 活跃会话采集器
+type activeSessionsCollector struct {
+	count func(ctx context.Context) (int64, error)
+}
+
+func (c *activeSessionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeSessionsDesc
+}
+
+func (c *activeSessionsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	n, err := c.count(ctx)
+	if err != nil {
+		logger.Debug("采集活跃会话数失败", zap.Error(err))
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(activeSessionsDesc, prometheus.GaugeValue, float64(n))
+}
+
+// RegisterCircuitBreaker 注册熔断器指标
+func RegisterCircuitBreaker(stats func() cache.BreakerStats) {
+	Register(&breakerCollector{stats: stats})
+}
+
+var (
+	breakerStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "circuit_breaker", "state"),
+		"熔断器状态，当前状态为 1，其它状态为 0", []string{"name", "state"}, nil)
+	breakerRejectedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "circuit_breaker", "rejected_total"),
+		"熔断期间被拒绝的请求数", []string{"name"}, nil)
+	breakerTripsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "circuit_breaker", "trips_total"),
+		"熔断器打开次数", []string{"name"}, nil)
+)
+
+// breakerCollector 熔断器采集器
+type breakerCollector struct {
+	stats func() cache.BreakerStats
+}
+
+func (c *breakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- breakerStateDesc
+	ch <- breakerRejectedDesc
+	ch <- breakerTripsDesc
+}
+
+func (c *breakerCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	for _, state := range []cache.BreakerState{cache.StateClosed, cache.StateOpen, cache.StateHalfOpen} {
+		value := 0.0
+		if state.String() == stats.State {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(breakerStateDesc, prometheus.GaugeValue, value, stats.Name, state.String())
+	}
+	ch <- prometheus.MustNewConstMetric(breakerRejectedDesc, prometheus.CounterValue, float64(stats.Rejected), stats.Name)
+	ch <- prometheus.MustNewConstMetric(breakerTripsDesc, prometheus.CounterValue, float64(stats.Trips), stats.Name)
+}
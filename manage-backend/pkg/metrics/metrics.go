@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// namespace 所有指标的前缀
+const namespace = "gms"
+
+// 登录结果
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+)
+
+// 登录失败原因
+const (
+	LoginReasonNone            = ""
+	LoginReasonInvalidCaptcha  = "invalid_captcha"
+	LoginReasonUserNotFound    = "user_not_found"
+	LoginReasonInvalidPassword = "invalid_password"
+	LoginReasonInternalError   = "internal_error"
+)
+
+// Registry 应用指标注册表
+// 使用独立注册表而不是全局默认注册表，避免第三方库的指标混入
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP 请求总数",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP 请求处理耗时（秒）",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	loginTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "login_total",
+		Help:      "登录次数，按结果和失败原因统计",
+	}, []string{"result", "reason"})
+
+	captchaGeneratedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "captcha",
+		Name:      "generated_total",
+		Help:      "验证码生成次数",
+	}, []string{"result"})
+
+	captchaVerifiedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "captcha",
+		Name:      "verified_total",
+		Help:      "验证码校验次数",
+	}, []string{"result"})
+
+	dbSlowQueriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "slow_queries_total",
+		Help:      "超过慢查询阈值的 SQL 次数，按语句类型统计",
+	}, []string{"operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		loginTotal,
+		captchaGeneratedTotal,
+		captchaVerifiedTotal,
+		dbSlowQueriesTotal,
+	)
+}
+
+// Register 注册自定义采集器
+// 重复注册时忽略（例如路由被多次初始化），其它错误仅记录日志，不影响服务启动
+func Register(c prometheus.Collector) {
+	if err := Registry.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			return
+		}
+		logger.Warn("注册指标采集器失败", zap.Error(err))
+	}
+}
+
+// Handler 返回指标暴露的 HTTP 处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveHTTPRequest 记录一次 HTTP 请求
+// route 应为路由模板（如 /api/v1/users/:id），避免路径参数导致标签基数爆炸
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	httpRequestsTotal.WithLabelValues(method, route, statusLabel).Inc()
+	httpRequestDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// RecordLogin 记录一次登录，成功时 reason 传 LoginReasonNone
+func RecordLogin(result, reason string) {
+	loginTotal.WithLabelValues(result, reason).Inc()
+}
+
+// RecordCaptchaGenerated 记录一次验证码生成
+func RecordCaptchaGenerated(err error) {
+	captchaGeneratedTotal.WithLabelValues(resultLabel(err == nil)).Inc()
+}
+
+// RecordCaptchaVerified 记录一次验证码校验
+func RecordCaptchaVerified(ok bool) {
+	captchaVerifiedTotal.WithLabelValues(resultLabel(ok)).Inc()
+}
+
+// RecordSlowQuery 记录一次慢查询，operation 为语句类型（SELECT、INSERT 等）
+func RecordSlowQuery(operation string) {
+	dbSlowQueriesTotal.WithLabelValues(operation).Inc()
+}
+
+func resultLabel(ok bool) string {
+	if ok {
+		return "success"
+	}
+	return "failure"
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestHandlerExposesApplicationMetrics(t *testing.T) {
+	ObserveHTTPRequest("GET", "/api/v1/users/:id", 200, 15*time.Millisecond)
+	RecordLogin(LoginFailure, LoginReasonInvalidPassword)
+	RecordCaptchaGenerated(nil)
+	RecordCaptchaVerified(false)
+	RecordSlowQuery("SELECT")
+
+	body := scrape(t)
+	assert.Contains(t, body, `gms_http_requests_total{method="GET",route="/api/v1/users/:id",status="200"} 1`)
+	assert.Contains(t, body, `gms_http_request_duration_seconds_count{method="GET",route="/api/v1/users/:id",status="200"} 1`)
+	assert.Contains(t, body, `gms_auth_login_total{reason="invalid_password",result="failure"} 1`)
+	assert.Contains(t, body, `gms_captcha_generated_total{result="success"} 1`)
+	assert.Contains(t, body, `gms_captcha_verified_total{result="failure"} 1`)
+	assert.Contains(t, body, `gms_db_slow_queries_total{operation="SELECT"} 1`)
+}
+
+func TestCustomCollectors(t *testing.T) {
+	RegisterActiveSessions(func(ctx context.Context) (int64, error) { return 3, nil })
+	RegisterCircuitBreaker(func() cache.BreakerStats {
+		return cache.BreakerStats{Name: "test-breaker", State: cache.StateOpen.String(), Trips: 2}
+	})
+	// 重复注册不会 panic
+	RegisterActiveSessions(func(ctx context.Context) (int64, error) { return 0, nil })
+
+	body := scrape(t)
+	assert.Contains(t, body, "gms_session_active 3")
+	assert.Contains(t, body, `gms_circuit_breaker_state{name="test-breaker",state="open"} 1`)
+	assert.Contains(t, body, `gms_circuit_breaker_state{name="test-breaker",state="closed"} 0`)
+	assert.Contains(t, body, `gms_circuit_breaker_trips_total{name="test-breaker"} 2`)
+}
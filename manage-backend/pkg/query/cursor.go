@@ -0,0 +1,289 @@
+package query
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidCursor 游标无法解析，或与当前排序不匹配
+var ErrInvalidCursor = fmt.Errorf("%w: invalid cursor", ErrInvalidSpec)
+
+// CountMode 总数统计方式
+type CountMode string
+
+const (
+	CountExact    CountMode = "exact"    // COUNT(*)，默认
+	CountEstimate CountMode = "estimate" // 无过滤条件时使用数据库统计信息估算，否则退回 COUNT(*)
+	CountNone     CountMode = "none"     // 不统计，Total 为 -1
+)
+
+// ParseCountMode 解析总数统计方式，空字符串为 CountExact
+func ParseCountMode(s string) (CountMode, error) {
+	switch mode := CountMode(s); mode {
+	case "":
+		return CountExact, nil
+	case CountExact, CountEstimate, CountNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w: unknown count mode %q", ErrInvalidSpec, s)
+	}
+}
+
+// CursorRequest 游标分页请求
+type CursorRequest struct {
+	Limit  int
+	Cursor string // 上一页响应中的 NextCursor 或 PrevCursor，为空表示第一页
+	Count  CountMode
+}
+
+// CursorPage 游标分页结果
+type CursorPage[T any] struct {
+	Items      []T
+	NextCursor string // 为空表示没有下一页
+	PrevCursor string // 为空表示没有上一页
+	// 总记录数，CountNone 时为 -1
+	Total          int64
+	TotalEstimated bool
+}
+
+// cursorToken 游标内容，编码为 base64url(JSON)，对客户端不透明
+type cursorToken struct {
+	Sort   string            `json:"s"`           // 排序签名，排序变化后旧游标失效
+	Values []json.RawMessage `json:"v"`           // 边界行的排序列取值
+	Prev   bool              `json:"p,omitempty"` // 向前翻页
+}
+
+// keysetColumn 游标分页的排序列
+type keysetColumn struct {
+	field *schema.Field
+	desc  bool
+}
+
+// FindPage 按 keyset 游标分页查询 T 的列表
+// 排序列为 spec 中的排序（或默认排序）加上 TieBreaker，排序列的值不能为 NULL；
+// 与 OFFSET 分页不同，翻页的开销与页码无关，翻页期间插入或删除数据也不会导致重复或遗漏
+func FindPage[T any](db *gorm.DB, sc Schema, spec Spec, req CursorRequest) (*CursorPage[T], error) {
+	if err := sc.Validate(spec); err != nil {
+		return nil, err
+	}
+	if req.Limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", ErrInvalidSpec)
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, fmt.Errorf("failed to parse model: %w", err)
+	}
+	columns, signature, err := sc.keysetColumns(stmt.Schema, spec.Sorts)
+	if err != nil {
+		return nil, err
+	}
+
+	var token *cursorToken
+	var bound []interface{}
+	if req.Cursor != "" {
+		if token, bound, err = decodeCursor(req.Cursor, signature, columns); err != nil {
+			return nil, err
+		}
+	}
+
+	page := &CursorPage[T]{}
+	if page.Total, page.TotalEstimated, err = sc.count(db, stmt.Schema.Table, spec, req.Count, new(T)); err != nil {
+		return nil, err
+	}
+
+	q, err := sc.Filter(db, spec)
+	if err != nil {
+		return nil, err
+	}
+	prev := token != nil && token.Prev
+	if token != nil {
+		q = q.Where(keysetCondition(columns, bound, prev))
+	}
+	order := make([]clause.OrderByColumn, len(columns))
+	for i, col := range columns {
+		// 向前翻页时反向排序，取到结果后再反转
+		order[i] = clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: col.field.DBName},
+			Desc:   col.desc != prev,
+		}
+	}
+
+	var items []T
+	if err := q.Order(clause.OrderBy{Columns: order}).Limit(req.Limit + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	hasMore := len(items) > req.Limit
+	if hasMore {
+		items = items[:req.Limit]
+	}
+	if prev {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	page.Items = items
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	// 向后翻页时，有更多数据才有下一页，带游标说明前面还有数据；向前翻页反之
+	hasNext, hasPrev := hasMore, token != nil
+	if prev {
+		hasNext, hasPrev = true, hasMore
+	}
+	ctx := db.Statement.Context
+	if hasNext {
+		if page.NextCursor, err = encodeCursor(ctx, signature, columns, items[len(items)-1], false); err != nil {
+			return nil, err
+		}
+	}
+	if hasPrev {
+		if page.PrevCursor, err = encodeCursor(ctx, signature, columns, items[0], true); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// keysetColumns 返回排序列（末尾为 TieBreaker）和排序签名
+func (sc Schema) keysetColumns(s *schema.Schema, sorts []Sort) ([]keysetColumn, string, error) {
+	if sc.TieBreaker == "" {
+		return nil, "", errors.New("cursor pagination requires a tie breaker column")
+	}
+
+	var columns []keysetColumn
+	var signature []string
+	for _, col := range sc.OrderColumns(sorts) {
+		field := s.LookUpField(col.Column.Name)
+		if field == nil {
+			return nil, "", fmt.Errorf("column %q not found in %s", col.Column.Name, s.Name)
+		}
+		columns = append(columns, keysetColumn{field: field, desc: col.Desc})
+		if col.Desc {
+			signature = append(signature, "-"+field.DBName)
+		} else {
+			signature = append(signature, field.DBName)
+		}
+	}
+	return columns, strings.Join(signature, ","), nil
+}
+
+// keysetCondition 构建 (c1, c2, ...) 在边界行之后（或之前）的条件
+// 各列排序方向可能不同，不能使用行值比较，展开为 c1 > v1 OR (c1 = v1 AND c2 > v2) OR ...
+func keysetCondition(columns []keysetColumn, values []interface{}, prev bool) clause.Expression {
+	var parts []string
+	var vars []interface{}
+	for i, col := range columns {
+		var conds []string
+		for j := 0; j < i; j++ {
+			conds = append(conds, "? = ?")
+			vars = append(vars, clause.Column{Table: clause.CurrentTable, Name: columns[j].field.DBName}, values[j])
+		}
+		op := ">"
+		if col.desc != prev {
+			op = "<"
+		}
+		conds = append(conds, "? "+op+" ?")
+		vars = append(vars, clause.Column{Table: clause.CurrentTable, Name: col.field.DBName}, values[i])
+		parts = append(parts, "("+strings.Join(conds, " AND ")+")")
+	}
+	return clause.Expr{SQL: "(" + strings.Join(parts, " OR ") + ")", Vars: vars}
+}
+
+// encodeCursor 根据边界行生成游标
+func encodeCursor(ctx context.Context, signature string, columns []keysetColumn, item interface{}, prev bool) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	rv := reflect.Indirect(reflect.ValueOf(item))
+
+	token := cursorToken{Sort: signature, Prev: prev}
+	for _, col := range columns {
+		value, _ := col.field.ValueOf(ctx, rv)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode cursor: %w", err)
+		}
+		token.Values = append(token.Values, raw)
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor 解析游标，按排序列的类型还原边界值（如 time.Time），保证比较时参数类型正确
+func decodeCursor(cursor, signature string, columns []keysetColumn) (*cursorToken, []interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+	if token.Sort != signature || len(token.Values) != len(columns) {
+		return nil, nil, fmt.Errorf("%w: sort order changed", ErrInvalidCursor)
+	}
+
+	values := make([]interface{}, len(columns))
+	for i, col := range columns {
+		v := reflect.New(col.field.IndirectFieldType)
+		if err := json.Unmarshal(token.Values[i], v.Interface()); err != nil {
+			return nil, nil, ErrInvalidCursor
+		}
+		values[i] = v.Elem().Interface()
+	}
+	return &token, values, nil
+}
+
+// count 按统计方式返回总数，estimated 表示结果来自统计信息
+func (sc Schema) count(db *gorm.DB, table string, spec Spec, mode CountMode, model interface{}) (total int64, estimated bool, err error) {
+	switch mode {
+	case CountNone:
+		return -1, false, nil
+	case CountEstimate:
+		if len(spec.Filters) == 0 {
+			if total, ok := estimateRows(db, table); ok {
+				return total, true, nil
+			}
+		}
+	}
+
+	q, err := sc.Filter(db.Model(model), spec)
+	if err != nil {
+		return 0, false, err
+	}
+	err = q.Count(&total).Error
+	return total, false, err
+}
+
+// estimateRows 从数据库统计信息读取表的估算行数，不支持或没有统计信息时返回 false
+func estimateRows(db *gorm.DB, table string) (int64, bool) {
+	var estimate *int64
+	var err error
+	switch db.Dialector.Name() {
+	case "postgres":
+		// 从未 ANALYZE 的表 reltuples 为 -1
+		err = db.Raw("SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass(?)", table).Scan(&estimate).Error
+	case "mysql":
+		err = db.Raw("SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", table).Scan(&estimate).Error
+	default:
+		return 0, false
+	}
+	if err != nil || estimate == nil || *estimate < 0 {
+		return 0, false
+	}
+	return *estimate, true
+}
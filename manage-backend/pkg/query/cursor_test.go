@@ -0,0 +1,134 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type cursorItem struct {
+	ID        uint `gorm:"primarykey"`
+	Name      string
+	Group     string
+	CreatedAt time.Time
+}
+
+var cursorSchema = Schema{
+	Fields: map[string]Field{
+		"id":         {Sortable: true},
+		"group":      {Ops: []Op{OpEq}, Sortable: true},
+		"created_at": {Sortable: true},
+	},
+	DefaultSort: []Sort{{Field: "id"}},
+	TieBreaker:  "id",
+}
+
+func newCursorTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&cursorItem{}))
+
+	// 创建时间有重复，验证 TieBreaker
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		require.NoError(t, db.Create(&cursorItem{
+			Name:      string(rune('a' + i)),
+			Group:     []string{"x", "y"}[i%2],
+			CreatedAt: base.Add(time.Duration(i/2) * time.Hour),
+		}).Error)
+	}
+	return db
+}
+
+func itemNames(items []cursorItem) string {
+	var s string
+	for _, it := range items {
+		s += it.Name
+	}
+	return s
+}
+
+func TestFindPageForwardAndBackward(t *testing.T) {
+	db := newCursorTestDB(t)
+	spec := Spec{Sorts: []Sort{{Field: "created_at", Desc: true}}}
+	req := CursorRequest{Limit: 3}
+
+	// 按创建时间倒序，相同时间按 id 升序: g ef cd ab
+	page, err := FindPage[cursorItem](db, cursorSchema, spec, req)
+	require.NoError(t, err)
+	assert.Equal(t, "gef", itemNames(page.Items))
+	assert.Equal(t, int64(7), page.Total)
+	assert.Empty(t, page.PrevCursor)
+	require.NotEmpty(t, page.NextCursor)
+
+	req.Cursor = page.NextCursor
+	page, err = FindPage[cursorItem](db, cursorSchema, spec, req)
+	require.NoError(t, err)
+	assert.Equal(t, "cda", itemNames(page.Items))
+	require.NotEmpty(t, page.NextCursor)
+	require.NotEmpty(t, page.PrevCursor)
+	second := page
+
+	req.Cursor = page.NextCursor
+	page, err = FindPage[cursorItem](db, cursorSchema, spec, req)
+	require.NoError(t, err)
+	assert.Equal(t, "b", itemNames(page.Items))
+	assert.Empty(t, page.NextCursor)
+
+	req.Cursor = page.PrevCursor
+	page, err = FindPage[cursorItem](db, cursorSchema, spec, req)
+	require.NoError(t, err)
+	assert.Equal(t, second.Items, page.Items)
+
+	req.Cursor = page.PrevCursor
+	page, err = FindPage[cursorItem](db, cursorSchema, spec, req)
+	require.NoError(t, err)
+	assert.Equal(t, "gef", itemNames(page.Items))
+	assert.Empty(t, page.PrevCursor)
+	assert.NotEmpty(t, page.NextCursor)
+}
+
+func TestFindPageFilterAndCount(t *testing.T) {
+	db := newCursorTestDB(t)
+	spec := Spec{Filters: []Filter{{Field: "group", Op: OpEq, Value: "x"}}}
+
+	page, err := FindPage[cursorItem](db, cursorSchema, spec, CursorRequest{Limit: 10, Count: CountNone})
+	require.NoError(t, err)
+	assert.Equal(t, "aceg", itemNames(page.Items))
+	assert.Equal(t, int64(-1), page.Total)
+	assert.Empty(t, page.NextCursor)
+
+	// SQLite 没有统计信息，退回精确统计
+	page, err = FindPage[cursorItem](db, cursorSchema, Spec{}, CursorRequest{Limit: 2, Count: CountEstimate})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), page.Total)
+	assert.False(t, page.TotalEstimated)
+}
+
+func TestFindPageRejectsInvalidCursor(t *testing.T) {
+	db := newCursorTestDB(t)
+
+	page, err := FindPage[cursorItem](db, cursorSchema, Spec{}, CursorRequest{Limit: 2})
+	require.NoError(t, err)
+
+	// 排序变化后旧游标失效
+	_, err = FindPage[cursorItem](db, cursorSchema, Spec{Sorts: []Sort{{Field: "group"}}}, CursorRequest{Limit: 2, Cursor: page.NextCursor})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", page.NextCursor[:len(page.NextCursor)-4]} {
+		_, err = FindPage[cursorItem](db, cursorSchema, Spec{}, CursorRequest{Limit: 2, Cursor: cursor})
+		assert.ErrorIs(t, err, ErrInvalidSpec, cursor)
+	}
+
+	_, err = ParseCountMode("approx")
+	assert.ErrorIs(t, err, ErrInvalidSpec)
+}
@@ -0,0 +1,258 @@
+// Package query 提供列表查询的过滤和排序规格（Spec）
+// 仓库通过 Schema 声明允许的字段、操作符和可排序字段，Spec 中的字段名只能取白名单中的值，
+// 列名由 Schema 映射并由数据库方言转义，过滤值统一作为参数绑定，避免 SQL 注入
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidSpec 查询规格不合法（字段不在白名单、操作符不支持等），调用方应返回 400
+var ErrInvalidSpec = errors.New("invalid query spec")
+
+// MaxSorts 最多允许的排序字段数量
+const MaxSorts = 5
+
+// Op 过滤操作符
+type Op string
+
+const (
+	OpEq       Op = "eq"       // 等于
+	OpIn       Op = "in"       // 属于列表，值为切片
+	OpContains Op = "contains" // 子串匹配（不区分大小写），值为字符串
+	OpGt       Op = "gt"       // 大于
+	OpGte      Op = "gte"      // 大于等于
+	OpLt       Op = "lt"       // 小于
+	OpLte      Op = "lte"      // 小于等于
+)
+
+// Filter 单个过滤条件
+type Filter struct {
+	Field string
+	Op    Op
+	Value interface{}
+}
+
+// Sort 单个排序字段
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Spec 列表查询规格，多个过滤条件之间为 AND 关系
+type Spec struct {
+	Filters []Filter
+	Sorts   []Sort
+}
+
+// Where 追加过滤条件
+func (s *Spec) Where(field string, op Op, value interface{}) *Spec {
+	s.Filters = append(s.Filters, Filter{Field: field, Op: op, Value: value})
+	return s
+}
+
+// Field 白名单字段
+type Field struct {
+	// 数据库列名，为空时与字段名相同
+	Column string
+	// 允许的过滤操作符，为空表示不可过滤
+	Ops []Op
+	// 是否允许排序
+	Sortable bool
+}
+
+// Schema 某个列表允许的查询字段
+type Schema struct {
+	Fields map[string]Field
+	// 未指定排序时使用的排序
+	DefaultSort []Sort
+	// 排序结果相同时追加的唯一列（通常是主键），保证分页顺序稳定
+	TieBreaker string
+}
+
+// ParseSort 解析排序参数，如 "-created_at,username"，字段名前加 - 表示降序
+// 只检查格式，字段是否允许排序由 Schema 校验
+func ParseSort(s string) ([]Sort, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var sorts []Sort
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		sort := Sort{Field: part}
+		if strings.HasPrefix(part, "-") {
+			sort = Sort{Field: part[1:], Desc: true}
+		} else if strings.HasPrefix(part, "+") {
+			sort.Field = part[1:]
+		}
+		if sort.Field == "" {
+			return nil, fmt.Errorf("%w: empty sort field", ErrInvalidSpec)
+		}
+		sorts = append(sorts, sort)
+	}
+	if len(sorts) > MaxSorts {
+		return nil, fmt.Errorf("%w: at most %d sort fields", ErrInvalidSpec, MaxSorts)
+	}
+	return sorts, nil
+}
+
+// Validate 校验规格中的字段和操作符是否在白名单中
+func (sc Schema) Validate(spec Spec) error {
+	for _, f := range spec.Filters {
+		field, ok := sc.Fields[f.Field]
+		if !ok {
+			return fmt.Errorf("%w: unknown filter field %q", ErrInvalidSpec, f.Field)
+		}
+		if !containsOp(field.Ops, f.Op) {
+			return fmt.Errorf("%w: operator %q is not allowed on %q", ErrInvalidSpec, f.Op, f.Field)
+		}
+		if f.Op == OpContains {
+			if _, ok := f.Value.(string); !ok {
+				return fmt.Errorf("%w: %q contains value must be a string", ErrInvalidSpec, f.Field)
+			}
+		}
+	}
+
+	if len(spec.Sorts) > MaxSorts {
+		return fmt.Errorf("%w: at most %d sort fields", ErrInvalidSpec, MaxSorts)
+	}
+	seen := make(map[string]bool, len(spec.Sorts))
+	for _, s := range spec.Sorts {
+		field, ok := sc.Fields[s.Field]
+		if !ok || !field.Sortable {
+			return fmt.Errorf("%w: cannot sort by %q", ErrInvalidSpec, s.Field)
+		}
+		if seen[s.Field] {
+			return fmt.Errorf("%w: duplicate sort field %q", ErrInvalidSpec, s.Field)
+		}
+		seen[s.Field] = true
+	}
+	return nil
+}
+
+// Filter 校验规格并添加过滤条件（不含排序，可用于 COUNT）
+func (sc Schema) Filter(db *gorm.DB, spec Spec) (*gorm.DB, error) {
+	if err := sc.Validate(spec); err != nil {
+		return nil, err
+	}
+	for _, f := range spec.Filters {
+		db = db.Where(sc.condition(f))
+	}
+	return db, nil
+}
+
+// Apply 校验规格并添加过滤条件和排序
+func (sc Schema) Apply(db *gorm.DB, spec Spec) (*gorm.DB, error) {
+	db, err := sc.Filter(db, spec)
+	if err != nil {
+		return nil, err
+	}
+	return db.Order(clause.OrderBy{Columns: sc.OrderColumns(spec.Sorts)}), nil
+}
+
+// OrderColumns 返回排序列：未指定时使用默认排序，最后追加 TieBreaker
+// 调用前应已通过 Validate 校验
+func (sc Schema) OrderColumns(sorts []Sort) []clause.OrderByColumn {
+	if len(sorts) == 0 {
+		sorts = sc.DefaultSort
+	}
+
+	columns := make([]clause.OrderByColumn, 0, len(sorts)+1)
+	hasTieBreaker := false
+	for _, s := range sorts {
+		column := sc.column(s.Field)
+		if column == sc.TieBreaker {
+			hasTieBreaker = true
+		}
+		columns = append(columns, clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: column},
+			Desc:   s.Desc,
+		})
+	}
+	if sc.TieBreaker != "" && !hasTieBreaker {
+		columns = append(columns, clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: sc.TieBreaker},
+		})
+	}
+	return columns
+}
+
+// column 返回字段对应的列名
+func (sc Schema) column(name string) string {
+	if field := sc.Fields[name]; field.Column != "" {
+		return field.Column
+	}
+	return name
+}
+
+// condition 构建单个过滤条件
+func (sc Schema) condition(f Filter) clause.Expression {
+	column := clause.Column{Table: clause.CurrentTable, Name: sc.column(f.Field)}
+
+	switch f.Op {
+	case OpIn:
+		return clause.IN{Column: column, Values: toValues(f.Value)}
+	case OpContains:
+		pattern := "%" + escapeLike(strings.ToLower(f.Value.(string))) + "%"
+		return clause.Expr{SQL: "LOWER(?) LIKE ? ESCAPE '!'", Vars: []interface{}{column, pattern}}
+	case OpGt:
+		return clause.Gt{Column: column, Value: f.Value}
+	case OpGte:
+		return clause.Gte{Column: column, Value: f.Value}
+	case OpLt:
+		return clause.Lt{Column: column, Value: f.Value}
+	case OpLte:
+		return clause.Lte{Column: column, Value: f.Value}
+	default:
+		return clause.Eq{Column: column, Value: f.Value}
+	}
+}
+
+// toValues 将切片转换为 IN 条件的值列表
+func toValues(v interface{}) []interface{} {
+	switch vs := v.(type) {
+	case []interface{}:
+		return vs
+	case []string:
+		values := make([]interface{}, len(vs))
+		for i, s := range vs {
+			values[i] = s
+		}
+		return values
+	case []uint:
+		values := make([]interface{}, len(vs))
+		for i, n := range vs {
+			values[i] = n
+		}
+		return values
+	case []int:
+		values := make([]interface{}, len(vs))
+		for i, n := range vs {
+			values[i] = n
+		}
+		return values
+	default:
+		return []interface{}{v}
+	}
+}
+
+// escapeLike 转义 LIKE 中的通配符
+// 使用 ! 作为转义字符：反斜杠在 MySQL 字符串字面量中本身需要转义，各数据库写法不一致
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+func containsOp(ops []Op, op Op) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
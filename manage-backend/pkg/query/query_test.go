@@ -0,0 +1,74 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+)
+
+func TestParseSort(t *testing.T) {
+	sorts, err := ParseSort(" -created_at, username ,+id")
+	require.NoError(t, err)
+	assert.Equal(t, []Sort{{Field: "created_at", Desc: true}, {Field: "username"}, {Field: "id"}}, sorts)
+
+	sorts, err = ParseSort("")
+	require.NoError(t, err)
+	assert.Empty(t, sorts)
+
+	_, err = ParseSort("name,-")
+	assert.ErrorIs(t, err, ErrInvalidSpec)
+	_, err = ParseSort("a,b,c,d,e,f")
+	assert.ErrorIs(t, err, ErrInvalidSpec)
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema := Schema{Fields: map[string]Field{
+		"name":  {Ops: []Op{OpEq, OpContains}, Sortable: true},
+		"notes": {Ops: []Op{OpContains}},
+	}}
+
+	assert.NoError(t, schema.Validate(Spec{
+		Filters: []Filter{{Field: "name", Op: OpContains, Value: "a"}},
+		Sorts:   []Sort{{Field: "name"}},
+	}))
+	for _, spec := range []Spec{
+		{Filters: []Filter{{Field: "secret", Op: OpEq, Value: "a"}}},
+		{Filters: []Filter{{Field: "name", Op: OpGt, Value: "a"}}},
+		{Filters: []Filter{{Field: "name", Op: OpContains, Value: 1}}},
+		{Sorts: []Sort{{Field: "notes"}}},
+		{Sorts: []Sort{{Field: "name"}, {Field: "name", Desc: true}}},
+	} {
+		assert.ErrorIs(t, schema.Validate(spec), ErrInvalidSpec)
+	}
+}
+
+func TestOrderColumns(t *testing.T) {
+	schema := Schema{
+		Fields: map[string]Field{
+			"id":      {Sortable: true},
+			"created": {Column: "created_at", Sortable: true},
+		},
+		DefaultSort: []Sort{{Field: "created", Desc: true}},
+		TieBreaker:  "id",
+	}
+
+	names := func(cols []clause.OrderByColumn) []string {
+		var out []string
+		for _, c := range cols {
+			name := c.Column.Name
+			if c.Desc {
+				name = "-" + name
+			}
+			out = append(out, name)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"-created_at", "id"}, names(schema.OrderColumns(nil)))
+	assert.Equal(t, []string{"-id"}, names(schema.OrderColumns([]Sort{{Field: "id", Desc: true}})))
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, "50!% off!!!_", escapeLike("50% off!_"))
+}
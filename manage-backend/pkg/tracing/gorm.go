@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	gormTracerName = "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/tracing/gorm"
+	gormSpanKey    = "tracing:span"
+)
+
+// GormPlugin 为每条 SQL 创建 span 的 GORM 插件
+// span 挂在 db.WithContext(ctx) 传入的上下文下，未传入上下文的查询会成为独立的 trace
+type GormPlugin struct {
+	dbSystem string
+	tracer   trace.Tracer
+}
+
+// 确保 GormPlugin 实现了 gorm.Plugin 接口
+var _ gorm.Plugin = (*GormPlugin)(nil)
+
+// DBSystem 将 GORM 方言名称转换为 OpenTelemetry 的 db.system 取值
+func DBSystem(dialect string) string {
+	if dialect == "postgres" {
+		return "postgresql"
+	}
+	return dialect
+}
+
+// NewGormPlugin 创建 GORM 追踪插件
+// dbSystem: 数据库类型，如 postgresql
+func NewGormPlugin(dbSystem string) *GormPlugin {
+	return &GormPlugin{
+		dbSystem: dbSystem,
+		tracer:   otel.Tracer(gormTracerName),
+	}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize 在 GORM 各类操作前后注册回调
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", p.before("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", p.after),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", p.before("select")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", p.after),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", p.before("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", p.after),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", p.before("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", p.after),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", p.before("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", p.after),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", p.before("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", p.after),
+	)
+}
+
+// before 开始 span，并把带 span 的上下文写回 Statement
+func (p *GormPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+		ctx, span := p.tracer.Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system.name", p.dbSystem),
+				attribute.String("db.operation.name", operation),
+			))
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+// after 记录 SQL、影响行数和错误后结束 span
+func (p *GormPlugin) after(db *gorm.DB) {
+	v, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	// SQL 使用占位符，不包含参数值
+	span.SetAttributes(
+		attribute.String("db.query.text", db.Statement.SQL.String()),
+		attribute.String("db.collection.name", db.Statement.Table),
+		attribute.Int64("db.response.returned_rows", db.Statement.RowsAffected),
+	)
+
+	// 记录不存在不视为错误
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
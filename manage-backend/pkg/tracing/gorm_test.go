@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+// newTracedDB 创建内存 SQLite 数据库，表创建完成后再注册追踪插件
+func newTracedDB(t *testing.T) (*gorm.DB, *tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&widget{}))
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	plugin := NewGormPlugin(DBSystem("sqlite"))
+	plugin.tracer = tp.Tracer("test")
+	require.NoError(t, db.Use(plugin))
+	return db, recorder, tp
+}
+
+func spanAttrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestGormPluginSpans(t *testing.T) {
+	db, recorder, tp := newTracedDB(t)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	require.NoError(t, db.WithContext(ctx).Create(&widget{Name: "a"}).Error)
+	var w widget
+	require.NoError(t, db.WithContext(ctx).Where("name = ?", "a").First(&w).Error)
+	require.NoError(t, db.WithContext(ctx).Model(&w).Update("name", "b").Error)
+	require.NoError(t, db.WithContext(ctx).Delete(&w).Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5)
+	for i, op := range []string{"create", "select", "update", "delete"} {
+		span := spans[i]
+		assert.Equal(t, "gorm."+op, span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), op)
+		assert.Equal(t, codes.Unset, span.Status().Code, op)
+
+		attrs := spanAttrs(span)
+		assert.Equal(t, "sqlite", attrs["db.system.name"].AsString())
+		assert.Equal(t, op, attrs["db.operation.name"].AsString())
+		assert.Equal(t, "widgets", attrs["db.collection.name"].AsString())
+		assert.Equal(t, int64(1), attrs["db.response.returned_rows"].AsInt64(), op)
+	}
+
+	// SQL 使用占位符，不包含参数值
+	query := spanAttrs(spans[1])["db.query.text"].AsString()
+	assert.Contains(t, query, "SELECT")
+	assert.Contains(t, query, "name = ?")
+	assert.NotContains(t, query, `"a"`)
+}
+
+func TestGormPluginErrorStatus(t *testing.T) {
+	db, recorder, _ := newTracedDB(t)
+	ctx := context.Background()
+
+	// 记录不存在不视为错误
+	var w widget
+	err := db.WithContext(ctx).First(&w, 42).Error
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	var rows []widget
+	err = db.WithContext(ctx).Table("missing").Find(&rows).Error
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Empty(t, spans[0].Events())
+
+	assert.Equal(t, "gorm.select", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Contains(t, spans[1].Status().Description, "no such table")
+	require.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, "exception", spans[1].Events()[0].Name)
+}
+
+func TestDBSystem(t *testing.T) {
+	assert.Equal(t, "postgresql", DBSystem("postgres"))
+	assert.Equal(t, "mysql", DBSystem("mysql"))
+	assert.Equal(t, "sqlite", DBSystem("sqlite"))
+}
@@ -0,0 +1,12 @@
+package tracing
+
+import (
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+// InstrumentRedis 为 Redis 客户端添加追踪钩子，每条命令生成一个 span
+// 不记录完整命令，避免会话、令牌等敏感值写入追踪系统
+func InstrumentRedis(client redis.UniversalClient) error {
+	return redisotel.InstrumentTracing(client, redisotel.WithDBStatement(false))
+}
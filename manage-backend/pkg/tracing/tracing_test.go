@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewExporter(t *testing.T) {
+	ctx := context.Background()
+
+	for _, name := range []string{config.TracingExporterOTLP, ""} {
+		exporter, err := newExporter(ctx, config.TracingConfig{Exporter: name, Endpoint: "localhost:4318", Insecure: true})
+		require.NoError(t, err)
+		assert.IsType(t, &otlptrace.Exporter{}, exporter, "exporter %q", name)
+		require.NoError(t, exporter.Shutdown(ctx))
+	}
+
+	exporter, err := newExporter(ctx, config.TracingConfig{Exporter: config.TracingExporterStdout})
+	require.NoError(t, err)
+	assert.IsType(t, &stdouttrace.Exporter{}, exporter)
+
+	_, err = newExporter(ctx, config.TracingConfig{Exporter: "zipkin"})
+	assert.ErrorContains(t, err, "unknown tracing exporter")
+}
+
+func TestInit(t *testing.T) {
+	ctx := context.Background()
+	original := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(original) })
+
+	// 未启用时不替换全局 TracerProvider
+	shutdown, err := Init(ctx, config.TracingConfig{Enabled: false, Exporter: "zipkin"}, "test")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(ctx))
+	assert.Equal(t, original, otel.GetTracerProvider())
+
+	_, err = Init(ctx, config.TracingConfig{Enabled: true, Exporter: "zipkin"}, "test")
+	assert.ErrorContains(t, err, "unknown tracing exporter")
+	assert.Equal(t, original, otel.GetTracerProvider())
+
+	shutdown, err = Init(ctx, config.TracingConfig{Enabled: true, Exporter: config.TracingExporterStdout}, "test")
+	require.NoError(t, err)
+	assert.IsType(t, &sdktrace.TracerProvider{}, otel.GetTracerProvider())
+	require.NoError(t, shutdown(ctx))
+}
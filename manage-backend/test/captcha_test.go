@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -48,7 +49,7 @@ func TestCaptchaService(t *testing.T) {
 	}
 
 	// 创建验证码服务
-	captchaService := service.NewCaptchaService(rdb, config)
+	captchaService := service.NewCaptchaService(cache.NewRedisStore(rdb), config)
 
 	t.Run("Generate Captcha", func(t *testing.T) {
 		// 生成验证码
@@ -87,7 +88,7 @@ func TestCaptchaService(t *testing.T) {
 		shortConfig := config
 		shortConfig.Expiration = 100 * time.Millisecond
 		
-		shortCaptchaService := service.NewCaptchaService(rdb, shortConfig)
+		shortCaptchaService := service.NewCaptchaService(cache.NewRedisStore(rdb), shortConfig)
 		
 		// 生成验证码
 		captcha, err := shortCaptchaService.GenerateCaptcha()
@@ -109,7 +110,7 @@ func TestCaptchaService(t *testing.T) {
 				typeConfig := config
 				typeConfig.Type = captchaType
 				
-				typeCaptchaService := service.NewCaptchaService(rdb, typeConfig)
+				typeCaptchaService := service.NewCaptchaService(cache.NewRedisStore(rdb), typeConfig)
 				
 				captcha, err := typeCaptchaService.GenerateCaptcha()
 				require.NoError(t, err)
@@ -149,7 +150,7 @@ func TestRedisCaptchaStore(t *testing.T) {
 		rdb.Close()
 	}()
 
-	store := service.NewRedisCaptchaStore(rdb, 1*time.Minute)
+	store := service.NewRedisCaptchaStore(cache.NewRedisStore(rdb), 1*time.Minute)
 
 	t.Run("Set and Get", func(t *testing.T) {
 		id := "test123"
@@ -210,4 +211,51 @@ func TestRedisCaptchaStore(t *testing.T) {
 		result2 := store.Verify("nonexistent", "value", false)
 		assert.False(t, result2)
 	})
-}
\ No newline at end of file
+}
+
+func TestCaptchaServiceWithMemoryStore(t *testing.T) {
+	// 使用内存存储，无需 Redis
+	memoryStore := cache.NewMemoryStore(time.Minute)
+	defer memoryStore.Close()
+
+	config := service.CaptchaConfig{
+		Type:            "digit",
+		Length:          5,
+		Width:           240,
+		Height:          80,
+		NoiseCount:      0.7,
+		ShowLineOptions: 80,
+		Expiration:      100 * time.Millisecond,
+		Enabled:         true,
+	}
+	captchaService := service.NewCaptchaService(memoryStore, config)
+
+	captcha, err := captchaService.GenerateCaptcha()
+	require.NoError(t, err)
+	assert.NotEmpty(t, captcha.CaptchaID)
+	assert.Contains(t, captcha.CaptchaData, "data:image/png;base64,")
+
+	// 答案已写入内存存储
+	answer, err := memoryStore.Get(context.Background(), "captcha:"+captcha.CaptchaID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, answer)
+
+	// 错误答案验证失败，且验证后清除
+	assert.False(t, captchaService.VerifyCaptcha(captcha.CaptchaID, "wrong"))
+	assert.False(t, captchaService.VerifyCaptcha(captcha.CaptchaID, answer))
+
+	// 正确答案验证成功
+	captcha, err = captchaService.GenerateCaptcha()
+	require.NoError(t, err)
+	answer, err = memoryStore.Get(context.Background(), "captcha:"+captcha.CaptchaID)
+	require.NoError(t, err)
+	assert.True(t, captchaService.VerifyCaptcha(captcha.CaptchaID, answer))
+
+	// 过期后验证失败
+	captcha, err = captchaService.GenerateCaptcha()
+	require.NoError(t, err)
+	answer, err = memoryStore.Get(context.Background(), "captcha:"+captcha.CaptchaID)
+	require.NoError(t, err)
+	time.Sleep(150 * time.Millisecond)
+	assert.False(t, captchaService.VerifyCaptcha(captcha.CaptchaID, answer))
+}
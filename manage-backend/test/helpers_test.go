@@ -0,0 +1,36 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/repository"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// newTestUserRepository 创建基于 SQLite 内存数据库的用户仓库，并在测试期间关闭日志输出
+func newTestUserRepository(t *testing.T) (*repository.UserRepository, *gorm.DB) {
+	t.Helper()
+	original := logger.Logger
+	logger.Logger = zap.NewNop()
+	t.Cleanup(func() { logger.Logger = original })
+
+	db, err := database.Init(config.Database{
+		Driver: config.DatabaseDriverSQLite,
+		Name:   ":memory:",
+		Log:    config.DatabaseLogConfig{Level: config.DatabaseLogSilent},
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.User{}))
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return repository.NewUserRepository(db), db
+}
@@ -0,0 +1,50 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionCleanupWithMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	memoryStore := cache.NewMemoryStore(time.Minute)
+	defer memoryStore.Close()
+
+	sessionService := service.NewSessionService(memoryStore, nil, config.SessionConfig{
+		CleanupInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, sessionService.CreateSession(ctx, 1, "alice", "refresh-1", "", "127.0.0.1", "test"))
+	require.NoError(t, sessionService.CreateSession(ctx, 2, "bob", "refresh-2", "", "127.0.0.1", "test"))
+
+	count, err := sessionService.CountActiveSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// 模拟会话通过 TTL 过期：会话键被删除，但活跃会话集合中仍有该用户
+	require.NoError(t, memoryStore.Del(ctx, "user:session:2"))
+	count, err = sessionService.CountActiveSessions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	stop := sessionService.StartCleanup()
+	assert.Eventually(t, func() bool {
+		count, err := sessionService.CountActiveSessions(ctx)
+		return err == nil && count == 1
+	}, time.Second, 10*time.Millisecond)
+
+	stopCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, stop(stopCtx))
+	require.NoError(t, stop(stopCtx), "stop is idempotent")
+
+	members, err := memoryStore.SMembers(ctx, "user:sessions")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, members)
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/export"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/jobs"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestExportService 创建用户导出服务，数据库中有 alice(admin)、bob(user)、carol(user, inactive) 三个用户
+func newTestExportService(t *testing.T, syncMaxRows int) *service.UserExportService {
+	t.Helper()
+	ctx := context.Background()
+	repo, _ := newTestUserRepository(t)
+	for _, u := range []model.User{
+		{Username: "alice", Email: "alice@example.com", Role: "admin", Status: "active"},
+		{Username: "bob", Email: "bob@example.com", Role: "user", Status: "active"},
+		{Username: "carol", Email: "carol@example.com", Role: "user", Status: "inactive"},
+	} {
+		u.Password = "x"
+		require.NoError(t, repo.Create(ctx, &u))
+	}
+
+	jobManager, err := jobs.NewManager(jobs.Options{Dir: t.TempDir(), TTL: time.Hour, MaxConcurrent: 1})
+	require.NoError(t, err)
+	t.Cleanup(func() { jobManager.Shutdown(context.Background()) })
+	return service.NewUserExportService(repo, jobManager, syncMaxRows)
+}
+
+// readExportCSV 解析导出的 CSV（去掉 UTF-8 BOM）
+func readExportCSV(t *testing.T, data []byte) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestUserExportServiceWithSQLite(t *testing.T) {
+	ctx := context.Background()
+	exportService := newTestExportService(t, 100)
+
+	// 默认导出全部列，表头为中文
+	var buf bytes.Buffer
+	require.NoError(t, exportService.Export(ctx, &buf, service.UserExportRequest{Format: export.FormatCSV}, nil))
+	records := readExportCSV(t, buf.Bytes())
+	require.Len(t, records, 4)
+	assert.Equal(t, []string{"ID", "用户名", "邮箱", "角色", "状态", "创建时间", "更新时间"}, records[0])
+	assert.Equal(t, []string{"1", "alice", "alice@example.com", "admin", "active"}, records[1][:5])
+
+	// 指定列、英文表头，并按列表的过滤和排序条件导出
+	var spec query.Spec
+	spec.Where("role", query.OpEq, "user")
+	spec.Sorts = []query.Sort{{Field: "username", Desc: true}}
+	buf.Reset()
+	var progress int64
+	require.NoError(t, exportService.Export(ctx, &buf, service.UserExportRequest{
+		Spec:    spec,
+		Format:  export.FormatCSV,
+		Columns: []string{"email", "username"},
+		Lang:    "en",
+	}, func(n int64) { progress = n }))
+	assert.Equal(t, [][]string{
+		{"Email", "Username"},
+		{"carol@example.com", "carol"},
+		{"bob@example.com", "bob"},
+	}, readExportCSV(t, buf.Bytes()))
+	assert.Equal(t, int64(2), progress)
+
+	// 不支持的语言使用中文表头
+	buf.Reset()
+	require.NoError(t, exportService.Export(ctx, &buf, service.UserExportRequest{Format: export.FormatCSV, Columns: []string{"id"}, Lang: "fr"}, nil))
+	assert.Equal(t, []string{"ID"}, readExportCSV(t, buf.Bytes())[0])
+
+	for _, columns := range [][]string{{"password"}, {"id", "id"}} {
+		_, err := exportService.Start(ctx, 1, service.UserExportRequest{Format: export.FormatCSV, Columns: columns}, false)
+		assert.ErrorIs(t, err, service.ErrInvalidExport, "columns %v", columns)
+		err = exportService.Export(ctx, io.Discard, service.UserExportRequest{Format: export.FormatCSV, Columns: columns}, nil)
+		assert.ErrorIs(t, err, service.ErrInvalidExport, "columns %v", columns)
+	}
+
+	spec = query.Spec{}
+	spec.Where("phone", query.OpEq, "1")
+	_, err := exportService.Start(ctx, 1, service.UserExportRequest{Spec: spec, Format: export.FormatCSV}, false)
+	assert.ErrorIs(t, err, query.ErrInvalidSpec)
+}
+
+func TestUserExportServiceStartWithSQLite(t *testing.T) {
+	ctx := context.Background()
+	exportService := newTestExportService(t, 2)
+
+	// 结果数量不超过同步上限时由调用方直接导出
+	var active query.Spec
+	active.Where("status", query.OpEq, "active")
+	result, err := exportService.Start(ctx, 1, service.UserExportRequest{Spec: active, Format: export.FormatCSV}, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Nil(t, result.Job)
+
+	// async 为 true 时总是提交后台任务
+	result, err = exportService.Start(ctx, 1, service.UserExportRequest{Spec: active, Format: export.FormatCSV}, true)
+	require.NoError(t, err)
+	require.NotNil(t, result.Job)
+
+	// 超过同步上限时提交后台任务，只有提交者可以查看和下载
+	result, err = exportService.Start(ctx, 1, service.UserExportRequest{Format: export.FormatCSV, Columns: []string{"username"}}, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Total)
+	require.NotNil(t, result.Job)
+	assert.Equal(t, "text/csv; charset=utf-8", result.Job.ContentType)
+
+	var job jobs.Job
+	require.Eventually(t, func() bool {
+		job, err = exportService.GetJob(result.Job.ID, 1)
+		require.NoError(t, err)
+		return job.DoneAt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, jobs.StatusSucceeded, job.Status)
+	assert.Equal(t, int64(3), job.Progress)
+
+	_, err = exportService.GetJob(result.Job.ID, 2)
+	assert.ErrorIs(t, err, jobs.ErrNotFound)
+
+	_, file, err := exportService.OpenJob(result.Job.ID, 1)
+	require.NoError(t, err)
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"用户名"}, {"alice"}, {"bob"}, {"carol"}}, readExportCSV(t, data))
+}
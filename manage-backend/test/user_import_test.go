@@ -0,0 +1,174 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/handler"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/export"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserImportWithSQLite(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestUserRepository(t)
+	require.NoError(t, repo.Create(ctx, &model.User{Username: "alice", Email: "alice@example.com", Password: "x"}))
+
+	importService := service.NewUserImportService(repo)
+	csv := "username,email,password\n" +
+		"alice,new@example.com,Passw0rd!\n" +
+		"bob,Alice@Example.com,Passw0rd!\n" +
+		"carol,carol@example.com,Passw0rd!\n" +
+		"carol,carol2@example.com,weak\n"
+
+	report, err := importService.Import(ctx, service.UserImportRequest{File: strings.NewReader(csv), Format: export.FormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 1, report.Valid)
+	assert.Equal(t, 3, report.Invalid)
+	assert.False(t, report.Committed)
+	assert.Equal(t, []string{"username already exists"}, report.Rows[0].Errors)
+	assert.Equal(t, []string{"email already exists"}, report.Rows[1].Errors)
+	assert.Empty(t, report.Rows[2].Errors)
+	assert.Contains(t, report.Rows[3].Errors, "duplicate username in file (row 4)")
+
+	// 存在错误行时提交不创建任何用户
+	report, err = importService.Import(ctx, service.UserImportRequest{File: strings.NewReader(csv), Format: export.FormatCSV, Commit: true})
+	require.NoError(t, err)
+	assert.False(t, report.Committed)
+	exists, err := repo.CheckUsernameExists(ctx, "carol")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// 提交的行数上限低于校验上限
+	var b strings.Builder
+	b.WriteString("username,email\n")
+	for i := 0; i <= service.MaxImportCommitRows; i++ {
+		fmt.Fprintf(&b, "user%d,user%d@example.com\n", i, i)
+	}
+	_, err = importService.Import(ctx, service.UserImportRequest{File: strings.NewReader(b.String()), Format: export.FormatCSV, Commit: true})
+	assert.ErrorIs(t, err, service.ErrInvalidImport)
+	_, err = importService.Import(ctx, service.UserImportRequest{File: strings.NewReader(b.String()), Format: export.FormatCSV})
+	assert.NoError(t, err)
+}
+
+func TestUserImportCommitWithSQLite(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestUserRepository(t)
+	importService := service.NewUserImportService(repo)
+
+	csv := "username,email,password,role\n" +
+		"bob,bob@example.com,Passw0rd!,admin\n" +
+		"carol,carol@example.com,,\n"
+	report, err := importService.Import(ctx, service.UserImportRequest{
+		File:              strings.NewReader(csv),
+		Format:            export.FormatCSV,
+		Commit:            true,
+		GeneratePasswords: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, report.Committed)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, "admin", report.Rows[0].Role)
+	assert.Equal(t, "user", report.Rows[1].Role)
+
+	// 提供了密码的行不返回密码，生成的密码只返回一次
+	bob, carol := report.Rows[0], report.Rows[1]
+	assert.Empty(t, bob.Password)
+	assert.NotEmpty(t, carol.Password)
+
+	for _, row := range report.Rows {
+		require.NotZero(t, row.UserID)
+		user, err := repo.GetCredentials(ctx, row.Username)
+		require.NoError(t, err)
+		assert.Equal(t, row.UserID, user.ID)
+		assert.Equal(t, row.Role, user.Role)
+	}
+	user, err := repo.GetCredentials(ctx, "bob")
+	require.NoError(t, err)
+	assert.True(t, utils.CheckPassword("Passw0rd!", user.Password))
+	user, err = repo.GetCredentials(ctx, "carol")
+	require.NoError(t, err)
+	assert.True(t, utils.CheckPassword(carol.Password, user.Password))
+}
+
+func TestUserImportCommitRollsBackWithSQLite(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestUserRepository(t)
+	importService := service.NewUserImportService(repo)
+
+	// 已删除的用户不参与重复校验，但唯一索引仍然存在，插入时失败
+	deleted := &model.User{Username: "zed", Email: "zed@example.com", Password: "x"}
+	require.NoError(t, repo.Create(ctx, deleted))
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	csv := "username,email,password\n" +
+		"bob,bob@example.com,Passw0rd!\n" +
+		"carol,carol@example.com,Passw0rd!\n" +
+		"zed,zed2@example.com,Passw0rd!\n"
+	_, err := importService.Import(ctx, service.UserImportRequest{File: strings.NewReader(csv), Format: export.FormatCSV, Commit: true})
+	require.Error(t, err)
+
+	existing, err := repo.FindExistingUsernames(ctx, []string{"bob", "carol"})
+	require.NoError(t, err)
+	assert.Empty(t, existing, "no user is created when a batch fails")
+}
+
+func TestUserImportHandlerWithSQLite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo, _ := newTestUserRepository(t)
+	router := gin.New()
+	router.POST("/users/import", handler.NewUserImportHandler(service.NewUserImportService(repo)).ImportUsers)
+
+	post := func(query, csv string) (*httptest.ResponseRecorder, service.UserImportReport) {
+		t.Helper()
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, err := mw.CreateFormFile("file", "users.csv")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(csv))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/users/import"+query, &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var resp struct {
+			Data service.UserImportReport `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return rec, resp.Data
+	}
+
+	invalid := "username,email,password\nbob,not-an-email,Passw0rd!\n"
+	rec, report := post("", invalid)
+	assert.Equal(t, http.StatusOK, rec.Code, "dry-run reports errors with 200")
+	assert.Equal(t, 1, report.Invalid)
+
+	rec, report = post("?commit=true", invalid)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, 1, report.Invalid)
+	assert.False(t, report.Committed)
+
+	rec, report = post("?commit=true", "username,email,password\nbob,bob@example.com,Passw0rd!\n")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, report.Committed)
+	assert.Equal(t, 1, report.Created)
+
+	rec, _ = post("", "name,mail\nbob,bob@example.com\n")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}